@@ -1,11 +1,14 @@
 package server
 
 import (
+	"context"
 	"library-management/backend/internal/api"
-	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/config"
 	"library-management/backend/internal/database"
+	"library-management/backend/internal/notification"
+	"library-management/backend/internal/scheduler"
 	"log"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -28,7 +31,7 @@ func Start() {
 		panic(err)
 	}
 
-	err = db.AutoMigrate(&model.Library{}, &model.Users{}, &model.BookInventory{}, &model.RequestEvents{}, &model.IssueRegistry{})
+	err = database.Migrate(db)
 	if err != nil {
 		log.Fatal("failed to migrate DB")
 	}
@@ -37,7 +40,43 @@ func Start() {
 	// 	log.Fatal("failed to migrate DB")
 	// }
 
-	h := cfg.InitHandler(cfg.InitRepository(db))
+	tokenMaker, err := cfg.InitTokenMaker()
+	if err != nil {
+		log.Fatal("failed to create token maker")
+	}
+
+	repo := cfg.InitRepository(db)
+	err = repo.AdminRepository.BackfillBookCopies(context.Background())
+	if err != nil {
+		log.Fatal("failed to backfill book copies")
+	}
+
+	mailTransport, err := cfg.InitMailTransport()
+	if err != nil {
+		log.Fatal("failed to create mail transport")
+	}
+	mailer := notification.NewDispatcher(repo.OutboxRepository, mailTransport)
+
+	jobs := scheduler.NewScheduler(repo.JobRepository, 30*time.Second)
+	jobs.Register(scheduler.Job{Name: "mark-overdue-issues", Interval: 15 * time.Minute, Run: repo.AdminRepository.MarkOverdueIssues})
+	jobs.Register(scheduler.Job{Name: "expire-stale-requests", Interval: time.Hour, Run: func(ctx context.Context) error {
+		return repo.AdminRepository.ExpireStaleRequests(ctx, cfg.Library.RequestExpiry)
+	}})
+	jobs.Register(scheduler.Job{Name: "expire-holds", Interval: time.Minute, Run: repo.AdminRepository.ExpireHolds})
+	jobs.Register(scheduler.Job{Name: "accrue-overdue-fines", Interval: time.Hour, Run: repo.AdminRepository.AccrueOverdueFines})
+	jobs.Register(scheduler.Job{Name: "remind-due-soon", Interval: time.Hour, Run: func(ctx context.Context) error {
+		return repo.AdminRepository.RemindDueSoon(ctx, cfg.Library.DueReminder)
+	}})
+	jobs.Register(scheduler.Job{Name: "send-emails", Interval: 30 * time.Second, Run: mailer.DispatchPending})
+	err = jobs.Start(context.Background())
+	if err != nil {
+		log.Fatal("failed to start job scheduler")
+	}
+
+	broker := cfg.InitBroker()
+	broker.Start(context.Background())
+
+	h := cfg.InitHandler(repo, broker, tokenMaker)
 	api := api.NewAPI(cfg, h)
 	if err != nil {
 		log.Fatal("cannot create api server")
@@ -56,29 +56,41 @@ func (api *API) SetupRouter() {
 			ctx.JSON(http.StatusOK, gin.H{"message": "pong"})
 		})
 
+		baseRoute.GET("/.well-known/jwks.json", api.Handler.AuthHandler.JWKS)
 		baseRoute.POST("/create-library", api.Handler.OwnerHandler.CreateLibraryWithOwner)
 		authRoutes := baseRoute.Group("/auth")
 		{
 			authRoutes.POST("/login", api.Handler.AuthHandler.Login)
 			authRoutes.POST("/register", api.Handler.AuthHandler.ReaderSignup)
 			authRoutes.GET("/refresh", api.Handler.AuthHandler.RefreshAccessToken)
+			authRoutes.POST("/logout", api.Handler.AuthHandler.Logout)
 
 		}
 
 		protectedRoutes := baseRoute.Group("/protected")
-		protectedRoutes.Use(middleware.JWTAuth())
+		protectedRoutes.Use(middleware.JWTAuth(api.Handler.TokenMaker))
 		{
 			protectedRoutes.POST("/book", api.Handler.SharedHandler.SearchBook)
 			protectedRoutes.GET("/book/:isbn", api.Handler.SharedHandler.SearchBookByISBN)
 			protectedRoutes.GET("/books", api.Handler.SharedHandler.GetBooks)
 
 			protectedRoutes.GET("/me", api.Handler.AuthHandler.UserDetails)
+			protectedRoutes.POST("/change-password", api.Handler.AuthHandler.ChangePassword)
+			protectedRoutes.GET("/notifications", api.Handler.SharedHandler.ListNotifications)
+			protectedRoutes.POST("/notifications/mark-read", api.Handler.SharedHandler.MarkNotificationsRead)
+			protectedRoutes.DELETE("/notifications/:notification_id", api.Handler.SharedHandler.DeleteNotification)
 			ownerRoutes := protectedRoutes.Group("/owner")
 			ownerRoutes.Use(middleware.RequirePrivilege(util.OwnerRole))
 			{
 				ownerRoutes.POST("/onboard-admin", api.Handler.OwnerHandler.CreateAdmin)
 				ownerRoutes.GET("/libraries", api.Handler.OwnerHandler.GetLibraries)
 				ownerRoutes.POST("/admins", api.Handler.OwnerHandler.GetAdmins)
+				ownerRoutes.POST("/reset-admin-password", api.Handler.OwnerHandler.ResetAdminPassword)
+				ownerRoutes.GET("/policy", api.Handler.OwnerHandler.GetLibraryPolicy)
+				ownerRoutes.PATCH("/update-policy", api.Handler.OwnerHandler.UpdateLibraryPolicy)
+				ownerRoutes.POST("/add-branch", api.Handler.OwnerHandler.AddBranch)
+				ownerRoutes.GET("/branches", api.Handler.OwnerHandler.ListBranches)
+				ownerRoutes.POST("/assign-admin-branch", api.Handler.OwnerHandler.AssignAdminBranch)
 			}
 			adminRoutes := protectedRoutes.Group("/admin")
 			adminRoutes.Use(middleware.RequirePrivilege(util.AdminRole))
@@ -86,9 +98,48 @@ func (api *API) SetupRouter() {
 				adminRoutes.POST("/add-book", api.Handler.AdminHandler.AddBook)
 				adminRoutes.POST("/remove-book", api.Handler.AdminHandler.RemoveBook)
 				adminRoutes.PATCH("/update-book", api.Handler.AdminHandler.UpdateBook)
+				adminRoutes.GET("/copy/:barcode", api.Handler.AdminHandler.GetCopy)
+				adminRoutes.GET("/copies/:isbn", api.Handler.AdminHandler.ListCopies)
+				adminRoutes.PATCH("/update-copy", api.Handler.AdminHandler.UpdateCopy)
 				adminRoutes.GET("/issue-requests", api.Handler.AdminHandler.ListIssueRequests)
+				adminRoutes.GET("/request-stream", api.Handler.AdminHandler.StreamRequests)
+				adminRoutes.GET("/requests/:request_id/history", api.Handler.AdminHandler.GetRequestHistory)
 				adminRoutes.POST("/approve-issue-request", api.Handler.AdminHandler.ApproveIssueRequest)
 				adminRoutes.POST("/reject-issue-request", api.Handler.AdminHandler.RejectIssueRequest)
+				adminRoutes.POST("/approve-return-request", api.Handler.AdminHandler.ApproveReturnRequest)
+				adminRoutes.POST("/reject-return-request", api.Handler.AdminHandler.RejectReturnRequest)
+				adminRoutes.POST("/approve-renewal-request", api.Handler.AdminHandler.ApproveRenewalRequest)
+				adminRoutes.POST("/reject-renewal-request", api.Handler.AdminHandler.RejectRenewalRequest)
+				adminRoutes.POST("/desk-checkout", api.Handler.AdminHandler.DeskCheckout)
+				adminRoutes.POST("/desk-checkin", api.Handler.AdminHandler.DeskCheckin)
+				adminRoutes.GET("/inter-library-loans", api.Handler.AdminHandler.ListInterLibraryLoans)
+				adminRoutes.POST("/ship-inter-library-loan", api.Handler.AdminHandler.ShipInterLibraryLoan)
+				adminRoutes.POST("/reject-inter-library-loan", api.Handler.AdminHandler.RejectInterLibraryLoan)
+				adminRoutes.POST("/receive-inter-library-loan", api.Handler.AdminHandler.ReceiveInterLibraryLoan)
+				adminRoutes.POST("/issue-inter-library-loan", api.Handler.AdminHandler.IssueInterLibraryLoan)
+				adminRoutes.POST("/receive-inter-library-return", api.Handler.AdminHandler.ReceiveInterLibraryReturn)
+				adminRoutes.GET("/branches", api.Handler.AdminHandler.ListBranches)
+				adminRoutes.POST("/transfer-copy", api.Handler.AdminHandler.TransferCopy)
+				adminRoutes.POST("/receive-transfer", api.Handler.AdminHandler.ReceiveBranchTransfer)
+				adminRoutes.POST("/reset-reader-password", api.Handler.AdminHandler.ResetReaderPassword)
+				adminRoutes.GET("/fines/:reader_id", api.Handler.AdminHandler.GetReaderFines)
+				adminRoutes.GET("/readers", api.Handler.AdminHandler.SearchReaders)
+				adminRoutes.POST("/reissue-card", api.Handler.AdminHandler.ReissueCard)
+				adminRoutes.GET("/readers/:reader_id/loans", api.Handler.AdminHandler.GetReaderLoans)
+				adminRoutes.GET("/readers/:reader_id/loan-history", api.Handler.AdminHandler.GetReaderLoanHistory)
+				adminRoutes.GET("/readers/:reader_id/requests", api.Handler.AdminHandler.GetReaderRequests)
+				adminRoutes.POST("/record-fine-charge", api.Handler.AdminHandler.RecordFineCharge)
+				adminRoutes.POST("/record-fine-payment", api.Handler.AdminHandler.RecordFinePayment)
+				adminRoutes.POST("/waive-fine", api.Handler.AdminHandler.WaiveFine)
+				adminRoutes.POST("/declare-lost", api.Handler.AdminHandler.DeclareLost)
+				adminRoutes.POST("/declare-damaged", api.Handler.AdminHandler.DeclareDamaged)
+				adminRoutes.POST("/reverse-declaration", api.Handler.AdminHandler.ReverseLoanDeclaration)
+				adminRoutes.GET("/jobs", api.Handler.AdminHandler.ListJobs)
+				adminRoutes.POST("/add-tier", api.Handler.AdminHandler.AddTier)
+				adminRoutes.GET("/tiers", api.Handler.AdminHandler.ListTiers)
+				adminRoutes.PATCH("/update-tier", api.Handler.AdminHandler.UpdateTier)
+				adminRoutes.POST("/remove-tier", api.Handler.AdminHandler.RemoveTier)
+				adminRoutes.POST("/assign-tier", api.Handler.AdminHandler.AssignTier)
 
 			}
 			readerRoutes := protectedRoutes.Group("/reader")
@@ -96,6 +147,21 @@ func (api *API) SetupRouter() {
 			{
 				readerRoutes.GET("/latest/:isbn", api.Handler.ReaderHandler.GetLatestAvailability)
 				readerRoutes.POST("/request-issue", api.Handler.ReaderHandler.RaiseIssueRequest)
+				readerRoutes.POST("/request-return", api.Handler.ReaderHandler.RaiseReturnRequest)
+				readerRoutes.POST("/request-renewal", api.Handler.ReaderHandler.RaiseRenewalRequest)
+				readerRoutes.POST("/cancel-request", api.Handler.ReaderHandler.CancelRequest)
+				readerRoutes.POST("/place-hold", api.Handler.ReaderHandler.PlaceHold)
+				readerRoutes.GET("/holds", api.Handler.ReaderHandler.ListHolds)
+				readerRoutes.POST("/cancel-hold", api.Handler.ReaderHandler.CancelHold)
+				readerRoutes.GET("/fines", api.Handler.ReaderHandler.GetFines)
+				readerRoutes.GET("/loans", api.Handler.ReaderHandler.GetCurrentLoans)
+				readerRoutes.GET("/loan-history", api.Handler.ReaderHandler.GetLoanHistory)
+				readerRoutes.GET("/requests", api.Handler.ReaderHandler.GetRequests)
+				readerRoutes.GET("/requests/:request_id/history", api.Handler.ReaderHandler.GetRequestHistory)
+				readerRoutes.GET("/inter-library/:isbn", api.Handler.ReaderHandler.FindInterLibraryCopies)
+				readerRoutes.POST("/request-inter-library-loan", api.Handler.ReaderHandler.RequestInterLibraryLoan)
+				readerRoutes.GET("/inter-library-loans", api.Handler.ReaderHandler.GetInterLibraryLoans)
+				readerRoutes.POST("/cancel-inter-library-loan", api.Handler.ReaderHandler.CancelInterLibraryLoan)
 			}
 		}
 	}
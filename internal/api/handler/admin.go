@@ -1,22 +1,34 @@
 package handler
 
 import (
+	"io"
 	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/api/schema"
 	"library-management/backend/internal/database/repository"
+	"library-management/backend/internal/stream"
+	"library-management/backend/internal/util"
 	"library-management/backend/internal/util/token"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// streamHeartbeatInterval keeps idle event streams from being closed by
+// proxies between the dashboard and the API
+const streamHeartbeatInterval = 15 * time.Second
+
 type AdminHandler struct {
 	AdminRepository *repository.AdminRepository
+	JobRepository   *repository.JobRepository
+	Broker          *stream.Broker
 }
 
-func NewAdminHandler(admin *repository.AdminRepository) *AdminHandler {
+func NewAdminHandler(admin *repository.AdminRepository, jobs *repository.JobRepository, broker *stream.Broker) *AdminHandler {
 	return &AdminHandler{
 		AdminRepository: admin,
+		JobRepository:   jobs,
+		Broker:          broker,
 	}
 }
 
@@ -43,7 +55,21 @@ func (admin *AdminHandler) AddBook(ctx *gin.Context) {
 		AvailableCopies: 1,
 	}
 
-	err := admin.AdminRepository.AddBook(ctx, &book, request.AdminEmail)
+	barcode := request.Barcode
+	if barcode == "" {
+		barcode = util.RandomBarcode()
+	}
+	bookCopy := model.BookCopy{
+		Barcode:       barcode,
+		BookID:        request.ISBN,
+		Condition:     request.Condition,
+		ShelfLocation: request.ShelfLocation,
+		BranchID:      request.BranchID,
+		Status:        util.CopyStatusAvailable,
+		AddedDate:     time.Now().Format(time.RFC3339),
+	}
+
+	err := admin.AdminRepository.AddBook(ctx, &book, &bookCopy, request.AdminEmail)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -76,7 +102,7 @@ func (admin *AdminHandler) RemoveBook(ctx *gin.Context) {
 	}
 	userID := sessionPayload.(*token.Payload).UserID
 
-	err := admin.AdminRepository.RemoveBook(ctx, request.ISBN, userID)
+	err := admin.AdminRepository.RemoveBook(ctx, request.ISBN, request.Barcode, userID)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -155,9 +181,11 @@ func (admin *AdminHandler) ListIssueRequests(ctx *gin.Context) {
 
 func (admin *AdminHandler) ApproveIssueRequest(ctx *gin.Context) {
 	var request schema.RequestDetails
-	response := schema.RequiredResponseFields{
-		Status:  "error",
-		Message: "",
+	response := schema.IssueResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
 	}
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -166,9 +194,18 @@ func (admin *AdminHandler) ApproveIssueRequest(ctx *gin.Context) {
 		return
 	}
 
-	err := admin.AdminRepository.ApproveIssueRequest(ctx, request.RequestID, request.AdminID)
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ApproveIssueRequest(ctx, request.RequestID, userID, request.Barcode)
 	if err != nil {
 		response.Message = err.Error()
+		response.LoanLimit = loanLimitDetails(err)
 		ctx.JSON(http.StatusBadRequest, response)
 		return
 	}
@@ -191,7 +228,15 @@ func (admin *AdminHandler) RejectIssueRequest(ctx *gin.Context) {
 		return
 	}
 
-	err := admin.AdminRepository.RejectIssueRequest(ctx, request.RequestID)
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.RejectIssueRequest(ctx, request.RequestID, userID, request.Reason)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -202,3 +247,1353 @@ func (admin *AdminHandler) RejectIssueRequest(ctx *gin.Context) {
 	response.Message = "book issue request rejected"
 	ctx.JSON(http.StatusCreated, response)
 }
+
+func (admin *AdminHandler) ApproveReturnRequest(ctx *gin.Context) {
+	var request schema.RequestDetails
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ApproveReturnRequest(ctx, request.RequestID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "book return request approved"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) RejectReturnRequest(ctx *gin.Context) {
+	var request schema.RequestDetails
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.RejectReturnRequest(ctx, request.RequestID, userID, request.Reason)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "book return request rejected"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) ApproveRenewalRequest(ctx *gin.Context) {
+	var request schema.RequestDetails
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ApproveRenewalRequest(ctx, request.RequestID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "loan renewal request approved"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) RejectRenewalRequest(ctx *gin.Context) {
+	var request schema.RequestDetails
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.RejectRenewalRequest(ctx, request.RequestID, userID, request.Reason)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "loan renewal request rejected"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) ResetReaderPassword(ctx *gin.Context) {
+	var request schema.ResetPasswordRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	hashedPassword, err := util.HashPassword(request.NewPassword)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	err = admin.AdminRepository.ResetReaderPassword(ctx, request.Email, hashedPassword, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "reader password reset successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) GetCopy(ctx *gin.Context) {
+	var copyDetails model.BookCopyDetails
+	response := schema.GetCopyResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+		Copy: nil,
+	}
+
+	barcode := ctx.Param("barcode")
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in current context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.GetCopyByBarcode(ctx, barcode, &copyDetails, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "found copy with supplied barcode"
+	response.Copy = &copyDetails
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ListCopies(ctx *gin.Context) {
+	copies := make([]model.BookCopy, 0)
+	response := schema.ListCopiesResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+		Copies: &copies,
+	}
+
+	isbn := ctx.Param("isbn")
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in current context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ListCopies(ctx, isbn, &copies, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "retrieved copies successfuly"
+	response.Copies = &copies
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) UpdateCopy(ctx *gin.Context) {
+	var request schema.UpdateCopyRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.UpdateCopy(ctx, request.Barcode, request.Condition, request.ShelfLocation, request.Status, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "copy updated successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) RecordFineCharge(ctx *gin.Context) {
+	var request schema.RecordFineChargeRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	entry := model.FineLedgerEntry{
+		ReaderID:    request.ReaderID,
+		IssueID:     request.IssueID,
+		EntryType:   request.ChargeType,
+		AmountCents: request.AmountCents,
+		Note:        request.Note,
+	}
+	err := admin.AdminRepository.RecordFineEntry(ctx, &entry, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fine charged successfuly"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) RecordFinePayment(ctx *gin.Context) {
+	admin.recordFineCredit(ctx, util.FineTypePayment, "fine payment recorded successfuly")
+}
+
+func (admin *AdminHandler) WaiveFine(ctx *gin.Context) {
+	admin.recordFineCredit(ctx, util.FineTypeWaiver, "fine waived successfuly")
+}
+
+func (admin *AdminHandler) recordFineCredit(ctx *gin.Context, entryType string, successMessage string) {
+	var request schema.RecordFineCreditRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	entry := model.FineLedgerEntry{
+		ReaderID:    request.ReaderID,
+		IssueID:     request.IssueID,
+		EntryType:   entryType,
+		AmountCents: request.AmountCents,
+		Note:        request.Note,
+	}
+	err := admin.AdminRepository.RecordFineEntry(ctx, &entry, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = successMessage
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) GetReaderFines(ctx *gin.Context) {
+	entries := make([]model.FineLedgerEntry, 0)
+	response := schema.FinesResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	readerID := ctx.Param("reader_id")
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.GetReaderFines(ctx, readerID, &entries, &response.BalanceCents, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched reader fines successfuly"
+	response.Entries = &entries
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ListJobs(ctx *gin.Context) {
+	jobs := make([]model.ScheduledJob, 0)
+	response := schema.ListJobsResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	err := admin.JobRepository.ListJobs(ctx, &jobs)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched scheduled jobs successfuly"
+	response.Jobs = &jobs
+	ctx.JSON(http.StatusOK, response)
+}
+
+// StreamRequests pushes the request events of the admin's library as
+// Server-Sent Events until the client disconnects
+func (admin *AdminHandler) StreamRequests(ctx *gin.Context) {
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in current context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	adminID := sessionPayload.(*token.Payload).UserID
+
+	var libID string
+	err := admin.AdminRepository.GetAdminLibrary(ctx, adminID, &libID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	events, unsubscribe := admin.Broker.Subscribe(libID)
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Event, event)
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", time.Now().Format(time.RFC3339))
+			return true
+		}
+	})
+}
+
+func (admin *AdminHandler) GetReaderLoans(ctx *gin.Context) {
+	loans := make([]model.LoanDetails, 0)
+	response := schema.LoansResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	readerID := ctx.Param("reader_id")
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.GetReaderLoans(ctx, readerID, &loans, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched reader loans successfuly"
+	response.Loans = &loans
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) GetReaderLoanHistory(ctx *gin.Context) {
+	var query schema.LoanHistoryQuery
+	loans := make([]model.LoanDetails, 0)
+	response := schema.LoanHistoryResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = util.DefaultPageSize
+	}
+
+	readerID := ctx.Param("reader_id")
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.GetReaderLoanHistory(ctx, readerID, query.Page, query.PageSize, &loans, &response.Total, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched reader loan history successfuly"
+	response.Loans = &loans
+	response.Page = query.Page
+	response.PageSize = query.PageSize
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) GetReaderRequests(ctx *gin.Context) {
+	var query schema.RequestsQuery
+	requests := make([]model.RequestDetails, 0)
+	response := schema.RequestsResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	readerID := ctx.Param("reader_id")
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.GetReaderRequests(ctx, readerID, query.Status, &requests, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched reader requests successfuly"
+	response.Requests = &requests
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) GetRequestHistory(ctx *gin.Context) {
+	changes := make([]model.RequestStatusChange, 0)
+	response := schema.RequestHistoryResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.GetRequestHistory(ctx, ctx.Param("request_id"), &changes, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched request history successfuly"
+	response.Changes = &changes
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) AddTier(ctx *gin.Context) {
+	var request schema.AddTierRequest
+	response := schema.TierResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	tier := model.MembershipTier{
+		Name:               request.Name,
+		LoanPeriodDays:     request.LoanPeriodDays,
+		MaxConcurrentLoans: request.MaxConcurrentLoans,
+		MaxRenewals:        request.MaxRenewals,
+		DailyFineCents:     request.DailyFineCents,
+	}
+	err := admin.AdminRepository.AddTier(ctx, &tier, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "tier added successfuly"
+	response.Tier = &tier
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) ListTiers(ctx *gin.Context) {
+	tiers := make([]model.MembershipTier, 0)
+	response := schema.ListTiersResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ListTiers(ctx, &tiers, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched tiers successfuly"
+	response.Tiers = &tiers
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) UpdateTier(ctx *gin.Context) {
+	var request schema.UpdateTierRequest
+	var tier model.MembershipTier
+	response := schema.TierResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	updates := make(map[string]interface{})
+	if request.Name != "" {
+		updates["name"] = request.Name
+	}
+	if request.LoanPeriodDays != nil {
+		updates["loan_period_days"] = *request.LoanPeriodDays
+	}
+	if request.MaxConcurrentLoans != nil {
+		updates["max_concurrent_loans"] = *request.MaxConcurrentLoans
+	}
+	if request.MaxRenewals != nil {
+		updates["max_renewals"] = *request.MaxRenewals
+	}
+	if request.DailyFineCents != nil {
+		updates["daily_fine_cents"] = *request.DailyFineCents
+	}
+
+	err := admin.AdminRepository.UpdateTier(ctx, request.TierID, updates, &tier, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "tier updated successfuly"
+	response.Tier = &tier
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) RemoveTier(ctx *gin.Context) {
+	var request schema.RemoveTierRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.RemoveTier(ctx, request.TierID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "tier removed successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) AssignTier(ctx *gin.Context) {
+	var request schema.AssignTierRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if request.MembershipExpiry != nil {
+		if _, err := time.Parse(time.RFC3339, *request.MembershipExpiry); err != nil {
+			response.Message = "membership_expiry must be an RFC3339 date"
+			ctx.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.AssignTier(ctx, request.ReaderID, request.TierID, request.MembershipExpiry, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "reader membership updated successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) DeclareLost(ctx *gin.Context) {
+	admin.declareLoan(ctx, util.IssueStatusLost)
+}
+
+func (admin *AdminHandler) DeclareDamaged(ctx *gin.Context) {
+	admin.declareLoan(ctx, util.IssueStatusDamaged)
+}
+
+func (admin *AdminHandler) declareLoan(ctx *gin.Context, disposition string) {
+	var request schema.DeclareLoanRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.DeclareLoan(ctx, request.IssueID, disposition, request.FeeCents, request.Note, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "loan declared " + disposition + " successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ReverseLoanDeclaration(ctx *gin.Context) {
+	var request schema.ReverseLoanDeclarationRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ReverseLoanDeclaration(ctx, request.IssueID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "loan declaration reversed successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) DeskCheckout(ctx *gin.Context) {
+	var request schema.DeskCheckoutRequest
+	var issue model.IssueRegistry
+	response := schema.DeskLoanResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.DeskCheckout(ctx, request.Reader, request.ISBN, request.Barcode, userID, &issue)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "book checked out successfuly"
+	response.IssueID = issue.IssueID
+	response.ReaderID = issue.ReaderID
+	response.Barcode = issue.CopyID
+	response.DueDate = issue.ExpectedReturnDate
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) DeskCheckin(ctx *gin.Context) {
+	var request schema.DeskCheckinRequest
+	var issue model.IssueRegistry
+	response := schema.DeskLoanResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.DeskCheckin(ctx, request.Barcode, userID, &issue)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "book checked in successfuly"
+	response.IssueID = issue.IssueID
+	response.ReaderID = issue.ReaderID
+	response.Barcode = issue.CopyID
+	response.DueDate = issue.ExpectedReturnDate
+	response.ReturnDate = issue.ReturnDate
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) SearchReaders(ctx *gin.Context) {
+	var query schema.SearchReadersQuery
+	readers := make([]model.Users, 0)
+	response := schema.SearchReadersResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.SearchReaders(ctx, query.Query, &readers, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched readers successfuly"
+	response.Readers = &readers
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ReissueCard(ctx *gin.Context) {
+	var request schema.ReissueCardRequest
+	var cardNumber string
+	response := schema.ReissueCardResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ReissueCard(ctx, request.ReaderID, &cardNumber, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "library card reissued successfuly"
+	response.CardNumber = cardNumber
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ListInterLibraryLoans(ctx *gin.Context) {
+	var query schema.InterLibraryLoansQuery
+	loans := make([]model.InterLibraryLoan, 0)
+	response := schema.InterLibraryLoansResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ListInterLibraryLoans(ctx, query.Status, &loans, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched inter-library loans successfuly"
+	response.Loans = &loans
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ShipInterLibraryLoan(ctx *gin.Context) {
+	var request schema.InterLibraryLoanAction
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ShipInterLibraryLoan(ctx, request.ILLID, request.Barcode, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "inter-library loan shipped successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) RejectInterLibraryLoan(ctx *gin.Context) {
+	var request schema.InterLibraryLoanAction
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.RejectInterLibraryLoan(ctx, request.ILLID, request.Reason, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "inter-library loan rejected successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ReceiveInterLibraryLoan(ctx *gin.Context) {
+	var request schema.InterLibraryLoanAction
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ReceiveInterLibraryLoan(ctx, request.ILLID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "inter-library loan received successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) IssueInterLibraryLoan(ctx *gin.Context) {
+	var request schema.InterLibraryLoanAction
+	var issue model.IssueRegistry
+	response := schema.DeskLoanResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.IssueInterLibraryLoan(ctx, request.ILLID, userID, &issue)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "inter-library loan issued successfuly"
+	response.IssueID = issue.IssueID
+	response.ReaderID = issue.ReaderID
+	response.Barcode = issue.CopyID
+	response.DueDate = issue.ExpectedReturnDate
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (admin *AdminHandler) ReceiveInterLibraryReturn(ctx *gin.Context) {
+	var request schema.InterLibraryLoanAction
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ReceiveInterLibraryReturn(ctx, request.ILLID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "inter-library return received successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ListBranches(ctx *gin.Context) {
+	branches := make([]model.Branch, 0)
+	response := schema.BranchesResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ListBranches(ctx, &branches, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched branches successfuly"
+	response.Branches = &branches
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) TransferCopy(ctx *gin.Context) {
+	var request schema.TransferCopyRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.TransferCopy(ctx, request.Barcode, request.BranchID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "copy transferred successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (admin *AdminHandler) ReceiveBranchTransfer(ctx *gin.Context) {
+	var request schema.ReceiveBranchTransferRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := admin.AdminRepository.ReceiveBranchTransfer(ctx, request.Barcode, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "copy received successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
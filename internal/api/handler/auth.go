@@ -9,8 +9,6 @@ import (
 	"library-management/backend/internal/util"
 	"library-management/backend/internal/util/token"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,12 +16,18 @@ import (
 )
 
 type AuthHandler struct {
-	AuthRepository *repository.AuthRepository
+	AuthRepository       *repository.AuthRepository
+	TokenMaker           token.Maker
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
 }
 
-func NewAuthHandler(auth *repository.AuthRepository) *AuthHandler {
+func NewAuthHandler(auth *repository.AuthRepository, tokenMaker token.Maker, accessTokenDuration time.Duration, refreshTokenDuration time.Duration) *AuthHandler {
 	return &AuthHandler{
-		AuthRepository: auth,
+		AuthRepository:       auth,
+		TokenMaker:           tokenMaker,
+		AccessTokenDuration:  accessTokenDuration,
+		RefreshTokenDuration: refreshTokenDuration,
 	}
 }
 
@@ -48,8 +52,8 @@ func (auth *AuthHandler) Login(ctx *gin.Context) {
 	err := auth.AuthRepository.Login(ctx, loginRequest.Email, &user)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			loginResponse.Message = "user not found with given email"
-			ctx.JSON(http.StatusNotFound, loginResponse)
+			loginResponse.Message = "invalid email or password"
+			ctx.JSON(http.StatusUnauthorized, loginResponse)
 			return
 		}
 		loginResponse.Message = "internal server error"
@@ -57,26 +61,33 @@ func (auth *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	jwtoken, err := token.NewJWTMaker(os.Getenv("JWT_SECRET_KEY"))
+	if err := util.CheckPassword(loginRequest.Password, user.HashedPassword); err != nil {
+		loginResponse.Message = "invalid email or password"
+		ctx.JSON(http.StatusUnauthorized, loginResponse)
+		return
+	}
+
+	accessToken, accessPayload, err := auth.TokenMaker.CreateToken(user.ID, user.Role, auth.AccessTokenDuration)
 	if err != nil {
 		loginResponse.Message = err.Error()
 		ctx.JSON(http.StatusInternalServerError, loginResponse)
 		return
 	}
 
-	duration, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_DURATION"))
+	refreshToken, refreshRecord, err := auth.newRefreshToken(user.ID, accessPayload.ID, util.RandomUUID())
 	if err != nil {
 		loginResponse.Message = err.Error()
 		ctx.JSON(http.StatusInternalServerError, loginResponse)
 		return
 	}
 
-	accessToken, _, err := jwtoken.CreateToken(user.ID, user.Role, duration)
+	err = auth.AuthRepository.CreateRefreshToken(ctx, refreshRecord)
 	if err != nil {
-		loginResponse.Message = err.Error()
+		loginResponse.Message = "internal server error"
 		ctx.JSON(http.StatusInternalServerError, loginResponse)
 		return
 	}
+	setRefreshTokenCookie(ctx, refreshToken, refreshRecord)
 
 	loginResponse.Status = "success"
 	loginResponse.Message = "login successful"
@@ -103,7 +114,8 @@ func (auth *AuthHandler) UserDetails(ctx *gin.Context) {
 	}
 
 	sessionPayload := session.(*token.Payload)
-	err := auth.AuthRepository.UserDetails(ctx, sessionPayload.UserID, &user)
+	var unreadNotifications int64
+	err := auth.AuthRepository.UserDetails(ctx, sessionPayload.UserID, &user, &unreadNotifications)
 	if err != nil {
 		response.Message = "internal server error"
 		ctx.JSON(http.StatusInternalServerError, response)
@@ -113,6 +125,7 @@ func (auth *AuthHandler) UserDetails(ctx *gin.Context) {
 	response.Status = "success"
 	response.Message = "user details fetched successfully"
 	response.User = &user
+	response.UnreadNotifications = unreadNotifications
 	ctx.JSON(http.StatusOK, response)
 }
 
@@ -130,16 +143,24 @@ func (auth *AuthHandler) ReaderSignup(ctx *gin.Context) {
 		return
 	}
 
+	hashedPassword, err := util.HashPassword(request.Password)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	newUser := model.Users{
-		ID:            util.RandomUUID(),
-		Name:          request.Name,
-		Email:         request.Email,
-		ContactNumber: request.Contact,
-		Role:          util.ReaderRole,
-		LibID:         &request.LibID,
+		ID:             util.RandomUUID(),
+		Name:           request.Name,
+		Email:          request.Email,
+		ContactNumber:  request.Contact,
+		Role:           util.ReaderRole,
+		HashedPassword: hashedPassword,
+		LibID:          &request.LibID,
 	}
 
-	err := auth.AuthRepository.UserSignup(ctx, newUser)
+	err = auth.AuthRepository.UserSignup(ctx, newUser)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -160,59 +181,163 @@ func (auth *AuthHandler) RefreshAccessToken(ctx *gin.Context) {
 		AccessToken: nil,
 	}
 
-	authorizationHeader := ctx.GetHeader("Authorization")
-	if len(authorizationHeader) == 0 {
-		response.Message = "authorization header not provided"
+	refreshToken, err := ctx.Cookie(middleware.RefreshTokenCookieKey)
+	if err != nil || len(refreshToken) == 0 {
+		response.Message = "refresh token not provided"
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	newToken, newRecord, err := auth.newRefreshToken("", "", "")
+	if err != nil {
+		response.Message = err.Error()
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	fields := strings.Fields(authorizationHeader)
-	if len(fields) < 2 {
-		response.Message = "invalid authorization header format"
+	var user model.Users
+	err = auth.AuthRepository.RotateRefreshToken(ctx, token.HashRefreshToken(refreshToken), newRecord, &user)
+	if err != nil {
+		clearRefreshTokenCookie(ctx)
+		response.Message = err.Error()
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	newAccessToken, accessPayload, err := auth.TokenMaker.CreateToken(user.ID, user.Role, auth.AccessTokenDuration)
+	if err != nil {
+		response.Message = err.Error()
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	authorizationType := strings.ToLower(fields[0])
-	if authorizationType != "bearer" {
-		response.Message = `unsupported authorization type, only "bearer" supported`
+	// the access token ID is only known once the new token is minted
+	newRecord.AccessTokenID = accessPayload.ID
+	err = auth.AuthRepository.UpdateRefreshTokenAccessID(ctx, newRecord.ID, accessPayload.ID)
+	if err != nil {
+		response.Message = "internal server error"
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
 	}
+	setRefreshTokenCookie(ctx, newToken, newRecord)
+
+	response.Status = "success"
+	response.Message = "token refreshed successfully"
+	response.AccessToken = &newAccessToken
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (auth *AuthHandler) Logout(ctx *gin.Context) {
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
 
-	accessToken := fields[1]
+	refreshToken, err := ctx.Cookie(middleware.RefreshTokenCookieKey)
+	if err != nil || len(refreshToken) == 0 {
+		response.Message = "refresh token not provided"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
 
-	tokenMaker, err := token.NewJWTMaker(os.Getenv("JWT_SECRET_KEY"))
+	clearRefreshTokenCookie(ctx)
+	err = auth.AuthRepository.RevokeRefreshToken(ctx, token.HashRefreshToken(refreshToken))
 	if err != nil {
 		response.Message = err.Error()
-		ctx.JSON(http.StatusInternalServerError, response)
+		ctx.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	payload, err := tokenMaker.VerifyToken(accessToken)
-	if err != nil && !errors.Is(err, token.ErrExpiredToken) {
-		response.Message = err.Error()
+	response.Status = "success"
+	response.Message = "logged out successfully"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (auth *AuthHandler) ChangePassword(ctx *gin.Context) {
+	var request schema.ChangePasswordRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	session, exists := ctx.Get(middleware.AuthorizationPayloadKey)
+	if !exists {
+		response.Message = "session not found in current context"
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
 	}
+	userID := session.(*token.Payload).UserID
 
-	duration, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_DURATION"))
+	hashedPassword, err := util.HashPassword(request.NewPassword)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	newAccessToken, _, err := tokenMaker.CreateToken(payload.UserID, payload.Role, duration)
+	err = auth.AuthRepository.ChangePassword(ctx, userID, request.OldPassword, hashedPassword)
 	if err != nil {
 		response.Message = err.Error()
-		ctx.JSON(http.StatusInternalServerError, response)
+		ctx.JSON(http.StatusBadRequest, response)
 		return
 	}
 
 	response.Status = "success"
-	response.Message = "token refreshed successfully"
-	response.AccessToken = &newAccessToken
+	response.Message = "password changed successfully"
 	ctx.JSON(http.StatusOK, response)
 }
+
+func (auth *AuthHandler) JWKS(ctx *gin.Context) {
+	keySetProvider, ok := auth.TokenMaker.(token.KeySetProvider)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "token signing keys are not published for the configured token type",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keySetProvider.JWKS())
+}
+
+func (auth *AuthHandler) newRefreshToken(userID string, accessTokenID string, familyID string) (string, *model.RefreshToken, error) {
+	refreshToken, err := token.NewRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	issuedAt := time.Now()
+	record := &model.RefreshToken{
+		ID:            util.RandomUUID(),
+		FamilyID:      familyID,
+		UserID:        userID,
+		HashedToken:   token.HashRefreshToken(refreshToken),
+		AccessTokenID: accessTokenID,
+		IssuedAt:      issuedAt.Format(time.RFC3339),
+		ExpiresAt:     issuedAt.Add(auth.RefreshTokenDuration).Format(time.RFC3339),
+		Revoked:       false,
+		ReplacedBy:    nil,
+	}
+	return refreshToken, record, nil
+}
+
+func setRefreshTokenCookie(ctx *gin.Context, refreshToken string, record *model.RefreshToken) {
+	maxAge := 0
+	if expiresAt, err := time.Parse(time.RFC3339, record.ExpiresAt); err == nil {
+		maxAge = int(time.Until(expiresAt).Seconds())
+	}
+
+	ctx.SetSameSite(http.SameSiteStrictMode)
+	ctx.SetCookie(middleware.RefreshTokenCookieKey, refreshToken, maxAge, middleware.RefreshTokenCookiePath, "", gin.Mode() == gin.ReleaseMode, true)
+}
+
+func clearRefreshTokenCookie(ctx *gin.Context) {
+	ctx.SetSameSite(http.SameSiteStrictMode)
+	ctx.SetCookie(middleware.RefreshTokenCookieKey, "", -1, middleware.RefreshTokenCookiePath, "", gin.Mode() == gin.ReleaseMode, true)
+}
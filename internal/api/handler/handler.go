@@ -2,6 +2,9 @@ package handler
 
 import (
 	"library-management/backend/internal/database/repository"
+	"library-management/backend/internal/stream"
+	"library-management/backend/internal/util/token"
+	"time"
 )
 
 type Handler struct {
@@ -10,14 +13,16 @@ type Handler struct {
 	AdminHandler  *AdminHandler
 	ReaderHandler *ReaderHandler
 	SharedHandler *SharedHandler
+	TokenMaker    token.Maker
 }
 
-func NewHandler(auth *repository.AuthRepository, owner *repository.OwnerRepository, admin *repository.AdminRepository, reader *repository.ReaderRepository, shared *repository.SharedRepository) *Handler {
+func NewHandler(auth *repository.AuthRepository, owner *repository.OwnerRepository, admin *repository.AdminRepository, reader *repository.ReaderRepository, shared *repository.SharedRepository, jobs *repository.JobRepository, broker *stream.Broker, tokenMaker token.Maker, accessTokenDuration time.Duration, refreshTokenDuration time.Duration) *Handler {
 	return &Handler{
-		AuthHandler:   NewAuthHandler(auth),
+		AuthHandler:   NewAuthHandler(auth, tokenMaker, accessTokenDuration, refreshTokenDuration),
 		OwnerHandler:  NewOwnerHandler(owner),
-		AdminHandler:  NewAdminHandler(admin),
+		AdminHandler:  NewAdminHandler(admin, jobs, broker),
 		ReaderHandler: NewReaderHandler(reader),
 		SharedHandler: NewSharedHandler(shared),
+		TokenMaker:    tokenMaker,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"library-management/backend/internal/api/schema"
 	"library-management/backend/internal/database/repository"
 	"library-management/backend/internal/util"
+	"library-management/backend/internal/util/token"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,13 @@ func (owner *OwnerHandler) CreateLibraryWithOwner(ctx *gin.Context) {
 		return
 	}
 
+	hashedPassword, err := util.HashPassword(request.Password)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	libID := util.RandomUUID()
 	ownerID := util.RandomUUID()
 
@@ -42,15 +50,16 @@ func (owner *OwnerHandler) CreateLibraryWithOwner(ctx *gin.Context) {
 	}
 
 	newOwner := model.Users{
-		ID:            ownerID,
-		Name:          request.Name,
-		Email:         request.Email,
-		ContactNumber: request.ContactNumber,
-		Role:          util.OwnerRole,
-		LibID:         &libID,
+		ID:             ownerID,
+		Name:           request.Name,
+		Email:          request.Email,
+		ContactNumber:  request.ContactNumber,
+		Role:           util.OwnerRole,
+		HashedPassword: hashedPassword,
+		LibID:          &libID,
 	}
 
-	err := owner.OwnerRepository.CreateLibraryWithUser(ctx, &newLibrary, &newOwner)
+	err = owner.OwnerRepository.CreateLibraryWithUser(ctx, &newLibrary, &newOwner)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -72,7 +81,15 @@ func (owner *OwnerHandler) GetLibraries(ctx *gin.Context) {
 		Libraries: &libraries,
 	}
 
-	err := owner.OwnerRepository.GetLibraries(ctx, &libraries)
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := owner.OwnerRepository.GetLibraries(ctx, userID, &libraries)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusInternalServerError, response)
@@ -130,16 +147,25 @@ func (owner *OwnerHandler) CreateAdmin(ctx *gin.Context) {
 		return
 	}
 
+	hashedPassword, err := util.HashPassword(request.Password)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	newUser := model.Users{
-		ID:            util.RandomUUID(),
-		Name:          request.Name,
-		Email:         request.Email,
-		ContactNumber: request.ContactNumber,
-		Role:          util.AdminRole,
-		LibID:         &request.LibID,
+		ID:             util.RandomUUID(),
+		Name:           request.Name,
+		Email:          request.Email,
+		ContactNumber:  request.ContactNumber,
+		Role:           util.AdminRole,
+		HashedPassword: hashedPassword,
+		LibID:          &request.LibID,
+		BranchID:       request.BranchID,
 	}
 
-	err := owner.OwnerRepository.OnboardAdmin(ctx, &newUser)
+	err = owner.OwnerRepository.OnboardAdmin(ctx, &newUser)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -150,3 +176,237 @@ func (owner *OwnerHandler) CreateAdmin(ctx *gin.Context) {
 	response.Message = "new admin onboarded successfuly"
 	ctx.JSON(http.StatusCreated, response)
 }
+
+func (owner *OwnerHandler) ResetAdminPassword(ctx *gin.Context) {
+	var request schema.ResetPasswordRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	hashedPassword, err := util.HashPassword(request.NewPassword)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	err = owner.OwnerRepository.ResetAdminPassword(ctx, request.Email, hashedPassword, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "admin password reset successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (owner *OwnerHandler) GetLibraryPolicy(ctx *gin.Context) {
+	var policy model.LibraryPolicy
+	response := schema.LibraryPolicyResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := owner.OwnerRepository.GetLibraryPolicy(ctx, userID, &policy)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched library policy successfuly"
+	response.Policy = &policy
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (owner *OwnerHandler) UpdateLibraryPolicy(ctx *gin.Context) {
+	var request schema.UpdateLibraryPolicyRequest
+	var policy model.LibraryPolicy
+	response := schema.LibraryPolicyResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	updates := make(map[string]interface{})
+	if request.LoanPeriodDays != nil {
+		updates["loan_period_days"] = *request.LoanPeriodDays
+	}
+	if request.MaxConcurrentLoans != nil {
+		updates["max_concurrent_loans"] = *request.MaxConcurrentLoans
+	}
+	if request.MaxRenewals != nil {
+		updates["max_renewals"] = *request.MaxRenewals
+	}
+	if request.AutoApproveRenewals != nil {
+		updates["auto_approve_renewals"] = *request.AutoApproveRenewals
+	}
+	if request.DailyFineCents != nil {
+		updates["daily_fine_cents"] = *request.DailyFineCents
+	}
+	if request.FineCapCents != nil {
+		updates["fine_cap_cents"] = *request.FineCapCents
+	}
+	if request.MaxBalanceCents != nil {
+		updates["max_balance_cents"] = *request.MaxBalanceCents
+	}
+
+	err := owner.OwnerRepository.UpdateLibraryPolicy(ctx, userID, updates, &policy)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "updated library policy successfuly"
+	response.Policy = &policy
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (owner *OwnerHandler) AddBranch(ctx *gin.Context) {
+	var request schema.AddBranchRequest
+	response := schema.BranchResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	branch := model.Branch{
+		Name:    request.Name,
+		Address: request.Address,
+	}
+
+	err := owner.OwnerRepository.AddBranch(ctx, userID, &branch)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "branch added successfuly"
+	response.Branch = &branch
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (owner *OwnerHandler) ListBranches(ctx *gin.Context) {
+	branches := make([]model.Branch, 0)
+	response := schema.BranchesResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := owner.OwnerRepository.ListBranches(ctx, userID, &branches)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "fetched branches successfuly"
+	response.Branches = &branches
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (owner *OwnerHandler) AssignAdminBranch(ctx *gin.Context) {
+	var request schema.AssignAdminBranchRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := owner.OwnerRepository.AssignAdminBranch(ctx, request.Email, request.BranchID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "admin branch assigned successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
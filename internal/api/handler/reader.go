@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/api/schema"
 	"library-management/backend/internal/database/repository"
+	"library-management/backend/internal/util"
 	"library-management/backend/internal/util/token"
 	"net/http"
 
@@ -21,6 +23,42 @@ func NewReaderHandler(reader *repository.ReaderRepository) *ReaderHandler {
 
 func (reader *ReaderHandler) RaiseIssueRequest(ctx *gin.Context) {
 	var request schema.RaiseIssueRequest
+	response := schema.IssueResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.RaiseIssueRequest(ctx, request.BookID, request.ReaderEmail, userID)
+	if err != nil {
+		response.Message = err.Error()
+		response.LoanLimit = loanLimitDetails(err)
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Raised Issue Request successfuly"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (reader *ReaderHandler) RaiseReturnRequest(ctx *gin.Context) {
+	var request schema.RaiseReturnRequest
 	response := schema.RequiredResponseFields{
 		Status:  "error",
 		Message: "",
@@ -40,7 +78,7 @@ func (reader *ReaderHandler) RaiseIssueRequest(ctx *gin.Context) {
 	}
 
 	userID := sessionPayload.(*token.Payload).UserID
-	err := reader.ReaderRepository.RaiseIssueRequest(ctx, request.BookID, request.ReaderEmail, userID)
+	err := reader.ReaderRepository.RaiseReturnRequest(ctx, request.BookID, userID)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -48,7 +86,7 @@ func (reader *ReaderHandler) RaiseIssueRequest(ctx *gin.Context) {
 	}
 
 	response.Status = "success"
-	response.Message = "Raised Issue Request successfuly"
+	response.Message = "Raised Return Request successfuly"
 	ctx.JSON(http.StatusCreated, response)
 }
 
@@ -63,7 +101,16 @@ func (reader *ReaderHandler) GetLatestAvailability(ctx *gin.Context) {
 		Date: &latestDate,
 	}
 
-	err := reader.ReaderRepository.GetLatestBookAvailability(ctx, isbn, &latestDate)
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	readerID := sessionPayload.(*token.Payload).UserID
+
+	err := reader.ReaderRepository.GetLatestBookAvailability(ctx, isbn, readerID, &latestDate)
 	if err != nil {
 		response.Message = err.Error()
 		ctx.JSON(http.StatusBadRequest, response)
@@ -75,3 +122,471 @@ func (reader *ReaderHandler) GetLatestAvailability(ctx *gin.Context) {
 	response.Date = &latestDate
 	ctx.JSON(http.StatusCreated, response)
 }
+
+func (reader *ReaderHandler) RaiseRenewalRequest(ctx *gin.Context) {
+	var request schema.RaiseRenewalRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	var autoApproved bool
+	err := reader.ReaderRepository.RaiseRenewalRequest(ctx, request.BookID, userID, &autoApproved)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Raised Renewal Request successfuly"
+	if autoApproved {
+		response.Message = "Renewed loan successfuly"
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (reader *ReaderHandler) PlaceHold(ctx *gin.Context) {
+	var request schema.PlaceHoldRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.PlaceHold(ctx, request.BookID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Placed hold successfuly"
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (reader *ReaderHandler) ListHolds(ctx *gin.Context) {
+	holds := make([]model.HoldDetails, 0)
+	response := schema.ListHoldsResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.ListHolds(ctx, userID, &holds)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched holds successfuly"
+	response.Holds = &holds
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) CancelHold(ctx *gin.Context) {
+	var request schema.CancelHoldRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.CancelHold(ctx, request.HoldID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Cancelled hold successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) CancelRequest(ctx *gin.Context) {
+	var request schema.CancelRequestRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.CancelRequest(ctx, request.RequestID, userID, request.Reason)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Cancelled request successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) GetRequestHistory(ctx *gin.Context) {
+	changes := make([]model.RequestStatusChange, 0)
+	response := schema.RequestHistoryResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.GetRequestHistory(ctx, ctx.Param("request_id"), userID, &changes)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched request history successfuly"
+	response.Changes = &changes
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) GetFines(ctx *gin.Context) {
+	entries := make([]model.FineLedgerEntry, 0)
+	response := schema.FinesResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.GetFines(ctx, userID, &entries, &response.BalanceCents)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched fines successfuly"
+	response.Entries = &entries
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) GetCurrentLoans(ctx *gin.Context) {
+	loans := make([]model.LoanDetails, 0)
+	response := schema.LoansResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.GetCurrentLoans(ctx, userID, &loans)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched current loans successfuly"
+	response.Loans = &loans
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) GetLoanHistory(ctx *gin.Context) {
+	var query schema.LoanHistoryQuery
+	loans := make([]model.LoanDetails, 0)
+	response := schema.LoanHistoryResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = util.DefaultPageSize
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.GetLoanHistory(ctx, userID, query.Page, query.PageSize, &loans, &response.Total)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched loan history successfuly"
+	response.Loans = &loans
+	response.Page = query.Page
+	response.PageSize = query.PageSize
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) GetRequests(ctx *gin.Context) {
+	var query schema.RequestsQuery
+	requests := make([]model.RequestDetails, 0)
+	response := schema.RequestsResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.GetRequests(ctx, userID, query.Status, &requests)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched requests successfuly"
+	response.Requests = &requests
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) FindInterLibraryCopies(ctx *gin.Context) {
+	libraries := make([]model.InterLibraryAvailability, 0)
+	response := schema.InterLibraryAvailabilityResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.FindInterLibraryCopies(ctx, ctx.Param("isbn"), userID, &libraries)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched lending libraries successfuly"
+	response.Libraries = &libraries
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) RequestInterLibraryLoan(ctx *gin.Context) {
+	var request schema.RequestInterLibraryLoanRequest
+	var loan model.InterLibraryLoan
+	response := schema.InterLibraryLoanResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.RequestInterLibraryLoan(ctx, request.BookID, request.LenderLibID, userID, &loan)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Requested inter-library loan successfuly"
+	response.Loan = &loan
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (reader *ReaderHandler) GetInterLibraryLoans(ctx *gin.Context) {
+	loans := make([]model.InterLibraryLoan, 0)
+	response := schema.InterLibraryLoansResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.GetInterLibraryLoans(ctx, userID, &loans)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Fetched inter-library loans successfuly"
+	response.Loans = &loans
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (reader *ReaderHandler) CancelInterLibraryLoan(ctx *gin.Context) {
+	var request schema.CancelInterLibraryLoanRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID := sessionPayload.(*token.Payload).UserID
+	err := reader.ReaderRepository.CancelInterLibraryLoan(ctx, request.ILLID, userID)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "Cancelled inter-library loan successfuly"
+	ctx.JSON(http.StatusOK, response)
+}
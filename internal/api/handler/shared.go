@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/api/schema"
 	"library-management/backend/internal/database/repository"
@@ -142,3 +143,108 @@ func (Shared *SharedHandler) GetBooks(ctx *gin.Context) {
 	response.Books = &books
 	ctx.JSON(http.StatusOK, response)
 }
+
+func (Shared *SharedHandler) ListNotifications(ctx *gin.Context) {
+	notifications := make([]model.Notification, 0)
+	response := schema.NotificationsResponse{
+		RequiredResponseFields: schema.RequiredResponseFields{
+			Status:  "error",
+			Message: "",
+		},
+		Notifications: &notifications,
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in current context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	unreadOnly := ctx.Query("unread") == "true"
+	err := Shared.SharedRepository.ListNotifications(ctx, userID, unreadOnly, &notifications)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "notifications fetched successfully"
+	response.Notifications = &notifications
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (Shared *SharedHandler) MarkNotificationsRead(ctx *gin.Context) {
+	var request schema.MarkNotificationsReadRequest
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.Message = "invalid request parameters"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in current context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := Shared.SharedRepository.MarkNotificationsRead(ctx, userID, request.NotificationIDs)
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "notifications marked as read"
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (Shared *SharedHandler) DeleteNotification(ctx *gin.Context) {
+	response := schema.RequiredResponseFields{
+		Status:  "error",
+		Message: "",
+	}
+
+	sessionPayload, ok := ctx.Get("session_payload")
+	if !ok {
+		response.Message = "session not found in current context"
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	userID := sessionPayload.(*token.Payload).UserID
+
+	err := Shared.SharedRepository.DeleteNotification(ctx, userID, ctx.Param("notification_id"))
+	if err != nil {
+		response.Message = err.Error()
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response.Status = "success"
+	response.Message = "notification deleted successfully"
+	ctx.JSON(http.StatusOK, response)
+}
+
+// loanLimitDetails extracts the counts behind a loan limit refusal so clients
+// can explain it to the reader
+func loanLimitDetails(err error) *schema.LoanLimitDetails {
+	var limitErr *repository.LoanLimitError
+	if !errors.As(err, &limitErr) {
+		return nil
+	}
+	return &schema.LoanLimitDetails{
+		Limit:           limitErr.Limit,
+		OpenLoans:       limitErr.OpenLoans,
+		PendingRequests: limitErr.PendingRequests,
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"library-management/backend/internal/database/repository"
 	"library-management/backend/internal/util/token"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +16,8 @@ const (
 	AuthorizationTypeBasic  = "basic"
 	AuthorizationTypeBearer = "bearer"
 	AuthorizationPayloadKey = "session_payload"
+	RefreshTokenCookieKey   = "refresh_token"
+	RefreshTokenCookiePath  = "/api/auth"
 )
 
 type AuthMiddleware struct {
@@ -33,8 +34,7 @@ func NewAuthMiddleware(auth *repository.AuthRepository) *AuthMiddleware {
 // 	BasicToken string `header:"Authorization"`
 // }
 
-func JWTAuth() gin.HandlerFunc {
-	tokenMaker, _ := token.NewJWTMaker(os.Getenv("JWT_SECRET_KEY"))
+func JWTAuth(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(AuthorizationHeaderKey)
 
@@ -9,7 +9,6 @@ import (
 	"library-management/backend/internal/util/token"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
@@ -44,13 +43,13 @@ func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, error) {
 func addAuthorization(
 	t *testing.T,
 	request *http.Request,
+	tokenMaker token.Maker,
 	authorizationType string,
 	userID string,
 	role string,
 	duration time.Duration,
 ) {
-	jwtoken, _ := token.NewJWTMaker(os.Getenv("JWT_SECRET_KEY"))
-	token, payload, err := jwtoken.CreateToken(userID, role, duration)
+	token, payload, err := tokenMaker.CreateToken(userID, role, duration)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, payload)
 
@@ -62,6 +61,10 @@ func TestAuthMiddleware(t *testing.T) {
 	userID := util.RandomUUID()
 	role := util.OwnerRole
 
+	cfg := &config.SampleEnv
+	tokenMaker, err := cfg.InitTokenMaker()
+	assert.NoError(t, err)
+
 	testCases := []struct {
 		name          string
 		setupAuth     func(t *testing.T, request *http.Request)
@@ -70,7 +73,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request) {
-				addAuthorization(t, request, middleware.AuthorizationTypeBearer, userID, role, time.Minute)
+				addAuthorization(t, request, tokenMaker, middleware.AuthorizationTypeBearer, userID, role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
@@ -87,7 +90,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request) {
-				addAuthorization(t, request, "unsupported", userID, role, time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", userID, role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -96,7 +99,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request) {
-				addAuthorization(t, request, "", userID, role, time.Minute)
+				addAuthorization(t, request, tokenMaker, "", userID, role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -105,7 +108,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request) {
-				addAuthorization(t, request, middleware.AuthorizationTypeBearer, userID, role, -time.Minute)
+				addAuthorization(t, request, tokenMaker, middleware.AuthorizationTypeBearer, userID, role, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -115,8 +118,7 @@ func TestAuthMiddleware(t *testing.T) {
 
 	db, _, err := setupTestDB(t)
 	assert.NoError(t, err)
-	cfg := &config.SampleEnv
-	h := config.SampleEnv.InitHandler(config.SampleEnv.InitRepository(db))
+	h := cfg.InitHandler(cfg.InitRepository(db), cfg.InitBroker(), tokenMaker)
 
 	for i := range testCases {
 		tc := testCases[i]
@@ -127,14 +129,14 @@ func TestAuthMiddleware(t *testing.T) {
 			authPath := "/auth/login"
 			api.Router.POST(
 				authPath,
-				middleware.JWTAuth(),
+				middleware.JWTAuth(tokenMaker),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
 			)
 
 			recorder := httptest.NewRecorder()
-			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			request, err := http.NewRequest(http.MethodPost, authPath, nil)
 			assert.NoError(t, err)
 
 			tc.setupAuth(t, request)
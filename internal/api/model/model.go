@@ -5,20 +5,62 @@ type Library struct {
 	Name string `gorm:"unique" json:"name" binding:"required"`
 }
 
+type LibraryPolicy struct {
+	LibID               string   `gorm:"primaryKey" json:"library_id"`
+	Library             *Library `gorm:"foreignKey:LibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	LoanPeriodDays      uint     `gorm:"" json:"loan_period_days"`
+	MaxConcurrentLoans  uint     `gorm:"" json:"max_concurrent_loans"`
+	MaxRenewals         uint     `gorm:"" json:"max_renewals"`
+	AutoApproveRenewals bool     `gorm:"" json:"auto_approve_renewals"`
+	DailyFineCents      uint     `gorm:"" json:"daily_fine_cents"`
+	FineCapCents        uint     `gorm:"" json:"fine_cap_cents"`
+	MaxBalanceCents     uint     `gorm:"default:1000" json:"max_balance_cents"`
+}
+
+// MembershipTier overrides the library policy for the readers assigned to it.
+// Renewal and fine limits left unset fall back to the library policy.
+type MembershipTier struct {
+	TierID             string   `gorm:"primaryKey" json:"tier_id"`
+	Library            *Library `gorm:"foreignKey:LibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	LibID              string   `gorm:"uniqueIndex:idx_tier_lib_name" json:"library_id"`
+	Name               string   `gorm:"uniqueIndex:idx_tier_lib_name" json:"name" binding:"required"`
+	LoanPeriodDays     uint     `gorm:"" json:"loan_period_days"`
+	MaxConcurrentLoans uint     `gorm:"" json:"max_concurrent_loans"`
+	MaxRenewals        *uint    `gorm:"" json:"max_renewals"`
+	DailyFineCents     *uint    `gorm:"" json:"daily_fine_cents"`
+}
+
+// Branch is a service point of a library. Copies and admins belong to a
+// branch, while readers may borrow and return at any branch of their library.
+type Branch struct {
+	BranchID string   `gorm:"primaryKey" json:"branch_id"`
+	Library  *Library `gorm:"foreignKey:LibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	LibID    string   `gorm:"uniqueIndex:idx_branch_lib_name" json:"library_id"`
+	Name     string   `gorm:"uniqueIndex:idx_branch_lib_name" json:"name" binding:"required"`
+	Address  string   `gorm:"" json:"address"`
+}
+
 type Users struct {
-	ID            string   `gorm:"primaryKey" json:"user_id" binding:"required"`
-	Name          string   `gorm:"" json:"name" binding:"required"`
-	Email         string   `gorm:"unique" json:"email" binding:"required"`
-	ContactNumber string   `gorm:"" json:"contact" binding:"required"`
-	Role          string   `gorm:"" json:"role" binding:"required"`
-	Library       *Library `gorm:"foreignKey:LibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
-	LibID         *string  `gorm:"" json:"library_id"`
+	ID               string          `gorm:"primaryKey" json:"user_id" binding:"required"`
+	Name             string          `gorm:"" json:"name" binding:"required"`
+	Email            string          `gorm:"unique" json:"email" binding:"required"`
+	ContactNumber    string          `gorm:"" json:"contact" binding:"required"`
+	Role             string          `gorm:"" json:"role" binding:"required"`
+	HashedPassword   string          `gorm:"" json:"-"`
+	Library          *Library        `gorm:"foreignKey:LibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	LibID            *string         `gorm:"" json:"library_id"`
+	CardNumber       *string         `gorm:"uniqueIndex" json:"card_number,omitempty"`
+	Tier             *MembershipTier `gorm:"foreignKey:TierID;references:TierID;constraint:OnDelete:SET NULL" json:"-"`
+	TierID           *string         `gorm:"index" json:"tier_id"`
+	MembershipExpiry *string         `gorm:"" json:"membership_expiry"`
+	Branch           *Branch         `gorm:"foreignKey:BranchID;references:BranchID;constraint:OnDelete:SET NULL" json:"-"`
+	BranchID         *string         `gorm:"index" json:"branch_id"`
 }
 
 type BookInventory struct {
 	ISBN            string   `gorm:"type:varchar(20);primaryKey" json:"isbn" binding:"required"`
 	Library         *Library `gorm:"foreignKey:LibID;references:ID" json:"-"`
-	LibID           *string  `gorm:"" json:"library_id" binding:"required"`
+	LibID           *string  `gorm:"primaryKey" json:"library_id" binding:"required"`
 	Title           string   `gorm:"" json:"title" binding:"required"`
 	Authors         string   `gorm:"" json:"authors" binding:"required"`
 	Publisher       string   `gorm:"" json:"publisher" binding:"required"`
@@ -27,10 +69,24 @@ type BookInventory struct {
 	AvailableCopies uint     `gorm:"" json:"available_copies" binding:"required"`
 }
 
+type BookCopy struct {
+	Barcode       string         `gorm:"type:varchar(32);primaryKey" json:"barcode" binding:"required"`
+	BookInventory *BookInventory `gorm:"foreignKey:BookID,LibID;references:ISBN,LibID" json:"-"`
+	BookID        string         `gorm:"index" json:"isbn" binding:"required"`
+	LibID         string         `gorm:"index" json:"library_id" binding:"required"`
+	Branch        *Branch        `gorm:"foreignKey:BranchID;references:BranchID;constraint:OnDelete:SET NULL" json:"-"`
+	BranchID      *string        `gorm:"index" json:"branch_id"`
+	Condition     string         `gorm:"" json:"condition"`
+	ShelfLocation string         `gorm:"" json:"shelf_location"`
+	Status        string         `gorm:"index" json:"status" binding:"required"`
+	AddedDate     string         `gorm:"" json:"added_date"`
+}
+
 type RequestEvents struct {
 	ReqID         string         `gorm:"primaryKey" json:"request_id" binding:"required"`
-	BookInventory *BookInventory `gorm:"foreignKey:BookID;references:ISBN" json:"-"`
+	BookInventory *BookInventory `gorm:"foreignKey:BookID,LibID;references:ISBN,LibID" json:"-"`
 	BookID        string         `gorm:"" json:"isbn" binding:"required"`
+	LibID         string         `gorm:"" json:"library_id"`
 	Reader        *Users         `gorm:"foreignKey:ReaderID;references:ID" json:"-"`
 	ReaderID      string         `gorm:"" json:"reader_id" binding:"required"`
 	RequestDate   string         `gorm:"" json:"request_date" binding:"required"`
@@ -38,12 +94,32 @@ type RequestEvents struct {
 	Admin         *Users         `gorm:"foreignKey:ApproverID;references:ID" json:"-"`
 	ApproverID    *string        `gorm:"" json:"approver_id,omitempty"`
 	RequestType   string         `gorm:"" json:"request_type,omitempty"`
+	Status        string         `gorm:"default:pending;index" json:"status"`
+	StatusReason  *string        `gorm:"" json:"status_reason,omitempty"`
+	ClosedDate    *string        `gorm:"" json:"closed_date,omitempty"`
+}
+
+type RequestStatusChange struct {
+	ChangeID    string         `gorm:"primaryKey" json:"change_id"`
+	Request     *RequestEvents `gorm:"foreignKey:ReqID;references:ReqID;constraint:OnDelete:CASCADE" json:"-"`
+	ReqID       string         `gorm:"index" json:"request_id"`
+	FromStatus  string         `gorm:"" json:"from_status,omitempty"`
+	ToStatus    string         `gorm:"" json:"to_status"`
+	Actor       *Users         `gorm:"foreignKey:ActorID;references:ID" json:"-"`
+	ActorID     *string        `gorm:"" json:"actor_id,omitempty"`
+	Reason      string         `gorm:"" json:"reason,omitempty"`
+	ChangedDate string         `gorm:"" json:"changed_date"`
 }
 
 type IssueRegistry struct {
 	IssueID            string         `gorm:"primaryKey"`
-	BookInventory      *BookInventory `gorm:"foreignKey:BookID;references:ISBN"`
+	BookInventory      *BookInventory `gorm:"foreignKey:BookID,LibID;references:ISBN,LibID"`
 	BookID             string         `gorm:""`
+	LibID              string         `gorm:""`
+	Branch             *Branch        `gorm:"foreignKey:BranchID;references:BranchID"`
+	BranchID           *string        `gorm:"index"`
+	BookCopy           *BookCopy      `gorm:"foreignKey:CopyID;references:Barcode"`
+	CopyID             *string        `gorm:""`
 	Reader             *Users         `gorm:"foreignKey:ReaderID;references:ID"`
 	ReaderID           string         `gorm:""`
 	AdminIssue         *Users         `gorm:"foreignKey:IssueApproverID;references:ID"`
@@ -51,16 +127,154 @@ type IssueRegistry struct {
 	IssueStatus        string         `gorm:""`
 	IssueDate          string         `gorm:""`
 	ExpectedReturnDate string         `gorm:""`
+	RenewalCount       uint           `gorm:"default:0"`
+	Overdue            bool           `gorm:"default:false"`
+	DueReminderSent    bool           `gorm:"default:false"`
 	ReturnDate         *string        `gorm:""`
 	AdminReturn        *Users         `gorm:"foreignKey:ReturnApproverID;references:ID"`
 	ReturnApproverID   *string        `gorm:""`
+	ReturnBranch       *Branch        `gorm:"foreignKey:ReturnBranchID;references:BranchID"`
+	ReturnBranchID     *string        `gorm:"index"`
+}
+
+type Hold struct {
+	HoldID         string         `gorm:"primaryKey" json:"hold_id"`
+	QueueNumber    uint64         `gorm:"autoIncrement;index" json:"-"`
+	BookInventory  *BookInventory `gorm:"foreignKey:BookID,LibID;references:ISBN,LibID" json:"-"`
+	BookID         string         `gorm:"index" json:"isbn"`
+	LibID          string         `gorm:"index" json:"library_id"`
+	Reader         *Users         `gorm:"foreignKey:ReaderID;references:ID" json:"-"`
+	ReaderID       string         `gorm:"index" json:"reader_id"`
+	BookCopy       *BookCopy      `gorm:"foreignKey:CopyID;references:Barcode" json:"-"`
+	CopyID         *string        `gorm:"" json:"barcode,omitempty"`
+	Status         string         `gorm:"index" json:"status"`
+	PlacedDate     string         `gorm:"" json:"placed_date"`
+	ReadyDate      *string        `gorm:"" json:"ready_date,omitempty"`
+	PickupDeadline *string        `gorm:"" json:"pickup_deadline,omitempty"`
+}
+
+type FineLedgerEntry struct {
+	EntryID     string         `gorm:"primaryKey" json:"entry_id"`
+	Reader      *Users         `gorm:"foreignKey:ReaderID;references:ID" json:"-"`
+	ReaderID    string         `gorm:"index" json:"reader_id"`
+	Library     *Library       `gorm:"foreignKey:LibID;references:ID" json:"-"`
+	LibID       string         `gorm:"index" json:"library_id"`
+	Issue       *IssueRegistry `gorm:"foreignKey:IssueID;references:IssueID" json:"-"`
+	IssueID     *string        `gorm:"index" json:"issue_id,omitempty"`
+	EntryType   string         `gorm:"" json:"entry_type"`
+	AmountCents int64          `gorm:"" json:"amount_cents"`
+	Note        string         `gorm:"" json:"note,omitempty"`
+	RecordedBy  *string        `gorm:"" json:"recorded_by,omitempty"`
+	EntryDate   string         `gorm:"" json:"entry_date"`
+}
+
+// InterLibraryLoan follows a copy lent by one library to a reader of another,
+// from the request through shipping and the local loan back to the lender
+type InterLibraryLoan struct {
+	ILLID             string   `gorm:"primaryKey" json:"ill_id"`
+	BookID            string   `gorm:"index" json:"isbn"`
+	Lender            *Library `gorm:"foreignKey:LenderLibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	LenderLibID       string   `gorm:"index" json:"lender_library_id"`
+	Borrower          *Library `gorm:"foreignKey:BorrowerLibID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	BorrowerLibID     string   `gorm:"index" json:"borrower_library_id"`
+	Reader            *Users   `gorm:"foreignKey:ReaderID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	ReaderID          string   `gorm:"index" json:"reader_id"`
+	CopyID            *string  `gorm:"" json:"copy_id,omitempty"`
+	IssueID           *string  `gorm:"index" json:"issue_id,omitempty"`
+	Status            string   `gorm:"index" json:"status"`
+	StatusReason      *string  `gorm:"" json:"status_reason,omitempty"`
+	RequestDate       string   `gorm:"" json:"request_date"`
+	ShippedDate       *string  `gorm:"" json:"shipped_date,omitempty"`
+	ReceivedDate      *string  `gorm:"" json:"received_date,omitempty"`
+	ReturnShippedDate *string  `gorm:"" json:"return_shipped_date,omitempty"`
+	ClosedDate        *string  `gorm:"" json:"closed_date,omitempty"`
+}
+
+type ScheduledJob struct {
+	Name            string  `gorm:"primaryKey" json:"name"`
+	IntervalSeconds int64   `gorm:"" json:"interval_seconds"`
+	NextRunAt       string  `gorm:"" json:"next_run_at"`
+	LastStartedAt   *string `gorm:"" json:"last_started_at,omitempty"`
+	LastFinishedAt  *string `gorm:"" json:"last_finished_at,omitempty"`
+	LastDurationMs  int64   `gorm:"" json:"last_duration_ms"`
+	LastError       *string `gorm:"" json:"last_error,omitempty"`
+	LastRunBy       *string `gorm:"" json:"last_run_by,omitempty"`
+	RunCount        uint    `gorm:"" json:"run_count"`
+	FailureCount    uint    `gorm:"" json:"failure_count"`
+}
+
+// RequestStreamEvent is pushed to admins watching their library's request
+// queue when a request is raised or decided
+type RequestStreamEvent struct {
+	Event       string `json:"event"`
+	RequestID   string `json:"request_id"`
+	RequestType string `json:"request_type"`
+	LibID       string `json:"library_id"`
+	BookID      string `json:"isbn"`
+	ReaderID    string `json:"reader_id"`
+	EventDate   string `json:"event_date"`
+}
+
+type Notification struct {
+	NotificationID string  `gorm:"primaryKey" json:"notification_id"`
+	User           *Users  `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID         string  `gorm:"index" json:"-"`
+	Kind           string  `gorm:"" json:"kind"`
+	Message        string  `gorm:"" json:"message"`
+	BookID         *string `gorm:"" json:"isbn,omitempty"`
+	RequestID      *string `gorm:"" json:"request_id,omitempty"`
+	Read           bool    `gorm:"default:false;index" json:"read"`
+	CreatedDate    string  `gorm:"" json:"created_date"`
+	ReadDate       *string `gorm:"" json:"read_date,omitempty"`
+}
+
+type OutboxEmail struct {
+	EmailID       string  `gorm:"primaryKey"`
+	User          *Users  `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	UserID        string  `gorm:"index"`
+	Recipient     string  `gorm:""`
+	Template      string  `gorm:""`
+	Data          string  `gorm:""`
+	Status        string  `gorm:"index"`
+	Attempts      uint    `gorm:"default:0"`
+	NextAttemptAt string  `gorm:""`
+	LastError     *string `gorm:""`
+	CreatedDate   string  `gorm:""`
+	SentDate      *string `gorm:""`
+}
+
+type RefreshToken struct {
+	ID            string  `gorm:"primaryKey"`
+	FamilyID      string  `gorm:"index"`
+	User          *Users  `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	UserID        string  `gorm:""`
+	HashedToken   string  `gorm:"unique"`
+	AccessTokenID string  `gorm:""`
+	IssuedAt      string  `gorm:""`
+	ExpiresAt     string  `gorm:""`
+	Revoked       bool    `gorm:""`
+	ReplacedBy    *string `gorm:""`
 }
 
 type LibraryDetails struct {
 	Library
-	OwnerName  string `json:"owner_name"`
-	OwnerEmail string `json:"owner_email"`
-	TotalBooks int    `json:"total_books"`
+	OwnerName  string          `json:"owner_name"`
+	OwnerEmail string          `json:"owner_email"`
+	TotalBooks int             `json:"total_books"`
+	Branches   []BranchDetails `gorm:"-" json:"branches"`
+}
+
+// BranchDetails is a branch with its staff, stock and circulation counts
+type BranchDetails struct {
+	Branch
+	Admins          int `json:"admins"`
+	TotalCopies     int `json:"total_copies"`
+	AvailableCopies int `json:"available_copies"`
+	IssuedCopies    int `json:"issued_copies"`
+	InTransitCopies int `json:"in_transit_copies"`
+	OpenLoans       int `json:"open_loans"`
+	LoansIssued     int `json:"loans_issued"`
+	LoansReturned   int `json:"loans_returned"`
 }
 
 type UpdateFields struct {
@@ -76,3 +290,44 @@ type IssueRequestDetails struct {
 	AvailableCopies int    `json:"available_copies" binding:"required"`
 	ReaderName      string `json:"reader_name" binding:"required"`
 }
+
+type BookCopyDetails struct {
+	BookCopy
+	BookTitle          string  `json:"book_title"`
+	IssueID            *string `json:"issue_id,omitempty"`
+	ReaderID           *string `json:"reader_id,omitempty"`
+	ReaderName         *string `json:"reader_name,omitempty"`
+	IssueDate          *string `json:"issue_date,omitempty"`
+	ExpectedReturnDate *string `json:"expected_return_date,omitempty"`
+}
+
+type HoldDetails struct {
+	Hold
+	BookTitle     string `json:"book_title"`
+	QueuePosition int    `json:"queue_position"`
+}
+
+type LoanDetails struct {
+	IssueID            string  `json:"issue_id"`
+	BookID             string  `json:"isbn"`
+	BookTitle          string  `json:"book_title"`
+	CopyID             *string `json:"copy_id,omitempty"`
+	IssueStatus        string  `json:"issue_status"`
+	IssueDate          string  `json:"issue_date"`
+	ExpectedReturnDate string  `json:"expected_return_date"`
+	ReturnDate         *string `json:"return_date,omitempty"`
+	RenewalCount       uint    `json:"renewal_count"`
+	Overdue            bool    `json:"overdue"`
+}
+
+type InterLibraryAvailability struct {
+	LibraryID       string `json:"library_id"`
+	LibraryName     string `json:"library_name"`
+	Title           string `json:"title"`
+	AvailableCopies uint   `json:"available_copies"`
+}
+
+type RequestDetails struct {
+	RequestEvents
+	BookTitle string `json:"book_title"`
+}
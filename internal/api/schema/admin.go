@@ -3,16 +3,21 @@ package schema
 import "library-management/backend/internal/api/model"
 
 type AddBookRequest struct {
-	AdminEmail string `json:"email" binding:"required"`
-	ISBN       string `json:"isbn" binding:"required"`
-	Title      string `json:"title" binding:"required"`
-	Authors    string `json:"authors" binding:"required"`
-	Publisher  string `json:"publisher" binding:"required"`
-	Version    string `json:"version" binding:"required"`
+	AdminEmail    string  `json:"email" binding:"required"`
+	ISBN          string  `json:"isbn" binding:"required"`
+	Title         string  `json:"title" binding:"required"`
+	Authors       string  `json:"authors" binding:"required"`
+	Publisher     string  `json:"publisher" binding:"required"`
+	Version       string  `json:"version" binding:"required"`
+	Barcode       string  `json:"barcode"`
+	Condition     string  `json:"condition"`
+	ShelfLocation string  `json:"shelf_location"`
+	BranchID      *string `json:"branch_id"`
 }
 
 type RemoveBookRequest struct {
-	ISBN string `json:"isbn" binding:"required"`
+	ISBN    string `json:"isbn" binding:"required"`
+	Barcode string `json:"barcode"`
 }
 
 type ListIssueRequestResponse struct {
@@ -22,7 +27,8 @@ type ListIssueRequestResponse struct {
 
 type RequestDetails struct {
 	RequestID string `json:"request_id" binding:"required"`
-	AdminID   string `json:"user_id" binding:"required"`
+	Barcode   string `json:"barcode"`
+	Reason    string `json:"reason"`
 }
 
 type UpdateBookRequest struct {
@@ -37,3 +43,139 @@ type GetBookByISBNResponse struct {
 	Status  string               `json:"status"`
 	Payload *model.BookInventory `json:"payload"`
 }
+
+type UpdateCopyRequest struct {
+	Barcode       string  `json:"barcode" binding:"required"`
+	Condition     *string `json:"condition"`
+	ShelfLocation *string `json:"shelf_location"`
+	Status        string  `json:"status"`
+}
+
+type GetCopyResponse struct {
+	RequiredResponseFields
+	Copy *model.BookCopyDetails `json:"copy,omitempty"`
+}
+
+type ListCopiesResponse struct {
+	RequiredResponseFields
+	Copies *[]model.BookCopy `json:"copies,omitempty"`
+}
+
+type RecordFineChargeRequest struct {
+	ReaderID    string  `json:"reader_id" binding:"required"`
+	IssueID     *string `json:"issue_id"`
+	ChargeType  string  `json:"charge_type" binding:"required,oneof=lost damaged"`
+	AmountCents int64   `json:"amount_cents" binding:"required,gt=0"`
+	Note        string  `json:"note"`
+}
+
+type RecordFineCreditRequest struct {
+	ReaderID    string  `json:"reader_id" binding:"required"`
+	IssueID     *string `json:"issue_id"`
+	AmountCents int64   `json:"amount_cents" binding:"required,gt=0"`
+	Note        string  `json:"note"`
+}
+
+type ListJobsResponse struct {
+	RequiredResponseFields
+	Jobs *[]model.ScheduledJob `json:"jobs,omitempty"`
+}
+
+type AddTierRequest struct {
+	Name               string `json:"name" binding:"required"`
+	LoanPeriodDays     uint   `json:"loan_period_days" binding:"required,min=1"`
+	MaxConcurrentLoans uint   `json:"max_concurrent_loans" binding:"required,min=1"`
+	MaxRenewals        *uint  `json:"max_renewals"`
+	DailyFineCents     *uint  `json:"daily_fine_cents"`
+}
+
+type UpdateTierRequest struct {
+	TierID             string `json:"tier_id" binding:"required"`
+	Name               string `json:"name"`
+	LoanPeriodDays     *uint  `json:"loan_period_days" binding:"omitempty,min=1"`
+	MaxConcurrentLoans *uint  `json:"max_concurrent_loans" binding:"omitempty,min=1"`
+	MaxRenewals        *uint  `json:"max_renewals"`
+	DailyFineCents     *uint  `json:"daily_fine_cents"`
+}
+
+type RemoveTierRequest struct {
+	TierID string `json:"tier_id" binding:"required"`
+}
+
+type AssignTierRequest struct {
+	ReaderID         string  `json:"reader_id" binding:"required"`
+	TierID           *string `json:"tier_id"`
+	MembershipExpiry *string `json:"membership_expiry"`
+}
+
+type TierResponse struct {
+	RequiredResponseFields
+	Tier *model.MembershipTier `json:"tier,omitempty"`
+}
+
+type ListTiersResponse struct {
+	RequiredResponseFields
+	Tiers *[]model.MembershipTier `json:"tiers,omitempty"`
+}
+
+type DeclareLoanRequest struct {
+	IssueID  string `json:"issue_id" binding:"required"`
+	FeeCents int64  `json:"fee_cents" binding:"gte=0"`
+	Note     string `json:"note"`
+}
+
+type ReverseLoanDeclarationRequest struct {
+	IssueID string `json:"issue_id" binding:"required"`
+}
+
+type DeskCheckoutRequest struct {
+	Reader  string `json:"reader" binding:"required"`
+	ISBN    string `json:"isbn" binding:"required"`
+	Barcode string `json:"barcode"`
+}
+
+type DeskCheckinRequest struct {
+	Barcode string `json:"barcode" binding:"required"`
+}
+
+type DeskLoanResponse struct {
+	RequiredResponseFields
+	IssueID    string  `json:"issue_id,omitempty"`
+	ReaderID   string  `json:"reader_id,omitempty"`
+	Barcode    *string `json:"barcode,omitempty"`
+	DueDate    string  `json:"due_date,omitempty"`
+	ReturnDate *string `json:"return_date,omitempty"`
+}
+
+type SearchReadersQuery struct {
+	Query string `form:"q" binding:"required"`
+}
+
+type SearchReadersResponse struct {
+	RequiredResponseFields
+	Readers *[]model.Users `json:"readers,omitempty"`
+}
+
+type ReissueCardRequest struct {
+	ReaderID string `json:"reader_id" binding:"required"`
+}
+
+type ReissueCardResponse struct {
+	RequiredResponseFields
+	CardNumber string `json:"card_number,omitempty"`
+}
+
+type InterLibraryLoanAction struct {
+	ILLID   string `json:"ill_id" binding:"required"`
+	Barcode string `json:"barcode"`
+	Reason  string `json:"reason"`
+}
+
+type TransferCopyRequest struct {
+	Barcode  string `json:"barcode" binding:"required"`
+	BranchID string `json:"branch_id" binding:"required"`
+}
+
+type ReceiveBranchTransferRequest struct {
+	Barcode string `json:"barcode" binding:"required"`
+}
@@ -8,7 +8,8 @@ type RequiredResponseFields struct {
 }
 
 type LoginRequest struct {
-	Email string `json:"email" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
 type LoginResponse struct {
 	RequiredResponseFields
@@ -18,14 +19,16 @@ type LoginResponse struct {
 
 type UserDetailsResponse struct {
 	RequiredResponseFields
-	User *model.Users `json:"user" binding:"required"`
+	User                *model.Users `json:"user" binding:"required"`
+	UnreadNotifications int64        `json:"unread_notifications"`
 }
 
 type ReaderSignupRequest struct {
-	LibID   string `json:"library_id" binding:"required"`
-	Name    string `json:"name" binding:"required"`
-	Email   string `json:"email" binding:"required"`
-	Contact string `json:"contact" binding:"required"`
+	LibID    string `json:"library_id" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Contact  string `json:"contact" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
 }
 type ReaderSignupResponse struct {
 	RequiredResponseFields
@@ -42,3 +45,13 @@ type RefreshAccessTokenResponse struct {
 	RequiredResponseFields
 	AccessToken *string `json:"access_token" binding:"required"`
 }
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type ResetPasswordRequest struct {
+	Email       string `json:"email" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
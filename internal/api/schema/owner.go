@@ -7,13 +7,16 @@ type CreateLibraryRequest struct {
 	Name          string `json:"name" binding:"required"`
 	Email         string `json:"email" binding:"required"`
 	ContactNumber string `json:"contact" binding:"required"`
+	Password      string `json:"password" binding:"required,min=8"`
 }
 
 type CreateAdminRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Email         string `json:"email" binding:"required"`
-	ContactNumber string `json:"contact" binding:"required"`
-	LibID         string `json:"library_id" binding:"required"`
+	Name          string  `json:"name" binding:"required"`
+	Email         string  `json:"email" binding:"required"`
+	ContactNumber string  `json:"contact" binding:"required"`
+	Password      string  `json:"password" binding:"required,min=8"`
+	LibID         string  `json:"library_id" binding:"required"`
+	BranchID      *string `json:"branch_id"`
 }
 
 type GetLibrariesResponse struct {
@@ -28,3 +31,33 @@ type GetAdminsResponse struct {
 	RequiredResponseFields
 	Admins *[]model.Users `json:"admins,omitempty"`
 }
+
+type LibraryPolicyResponse struct {
+	RequiredResponseFields
+	Policy *model.LibraryPolicy `json:"policy,omitempty"`
+}
+
+type UpdateLibraryPolicyRequest struct {
+	LoanPeriodDays      *uint `json:"loan_period_days" binding:"omitempty,min=1"`
+	MaxConcurrentLoans  *uint `json:"max_concurrent_loans" binding:"omitempty,min=1"`
+	MaxRenewals         *uint `json:"max_renewals"`
+	AutoApproveRenewals *bool `json:"auto_approve_renewals"`
+	DailyFineCents      *uint `json:"daily_fine_cents"`
+	FineCapCents        *uint `json:"fine_cap_cents"`
+	MaxBalanceCents     *uint `json:"max_balance_cents"`
+}
+
+type AddBranchRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+}
+
+type BranchResponse struct {
+	RequiredResponseFields
+	Branch *model.Branch `json:"branch,omitempty"`
+}
+
+type AssignAdminBranchRequest struct {
+	Email    string  `json:"email" binding:"required"`
+	BranchID *string `json:"branch_id"`
+}
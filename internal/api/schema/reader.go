@@ -1,5 +1,7 @@
 package schema
 
+import "library-management/backend/internal/api/model"
+
 type RaiseIssueRequest struct {
 	ReaderEmail string `json:"email" binding:"required"`
 	BookID      string `json:"isbn" binding:"required"`
@@ -9,3 +11,43 @@ type GetLatestAvailabilityResponse struct {
 	RequiredResponseFields
 	Date *string `json:"date,omitempty"`
 }
+
+type RaiseReturnRequest struct {
+	BookID string `json:"isbn" binding:"required"`
+}
+
+type RaiseRenewalRequest struct {
+	BookID string `json:"isbn" binding:"required"`
+}
+
+type PlaceHoldRequest struct {
+	BookID string `json:"isbn" binding:"required"`
+}
+
+type CancelHoldRequest struct {
+	HoldID string `json:"hold_id" binding:"required"`
+}
+
+type CancelRequestRequest struct {
+	RequestID string `json:"request_id" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+type ListHoldsResponse struct {
+	RequiredResponseFields
+	Holds *[]model.HoldDetails `json:"holds,omitempty"`
+}
+
+type InterLibraryAvailabilityResponse struct {
+	RequiredResponseFields
+	Libraries *[]model.InterLibraryAvailability `json:"libraries,omitempty"`
+}
+
+type RequestInterLibraryLoanRequest struct {
+	BookID      string `json:"isbn" binding:"required"`
+	LenderLibID string `json:"library_id" binding:"required"`
+}
+
+type CancelInterLibraryLoanRequest struct {
+	ILLID string `json:"ill_id" binding:"required"`
+}
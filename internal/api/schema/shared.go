@@ -16,3 +16,80 @@ type SearchBookByISBNResponse struct {
 	RequiredResponseFields
 	Book *model.BookInventory `json:"book,omitempty"`
 }
+
+type FinesResponse struct {
+	RequiredResponseFields
+	BalanceCents int64                    `json:"balance_cents"`
+	Entries      *[]model.FineLedgerEntry `json:"entries,omitempty"`
+}
+
+type NotificationsResponse struct {
+	RequiredResponseFields
+	Notifications *[]model.Notification `json:"notifications,omitempty"`
+}
+
+type MarkNotificationsReadRequest struct {
+	NotificationIDs []string `json:"notification_ids"`
+}
+
+type LoansResponse struct {
+	RequiredResponseFields
+	Loans *[]model.LoanDetails `json:"loans,omitempty"`
+}
+
+type LoanHistoryQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type LoanHistoryResponse struct {
+	RequiredResponseFields
+	Loans    *[]model.LoanDetails `json:"loans,omitempty"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int64                `json:"total"`
+}
+
+type RequestsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected expired cancelled"`
+}
+
+type RequestsResponse struct {
+	RequiredResponseFields
+	Requests *[]model.RequestDetails `json:"requests,omitempty"`
+}
+
+type RequestHistoryResponse struct {
+	RequiredResponseFields
+	Changes *[]model.RequestStatusChange `json:"changes,omitempty"`
+}
+
+type LoanLimitDetails struct {
+	Limit           uint  `json:"limit"`
+	OpenLoans       int64 `json:"open_loans"`
+	PendingRequests int64 `json:"pending_requests"`
+}
+
+type IssueResponse struct {
+	RequiredResponseFields
+	LoanLimit *LoanLimitDetails `json:"loan_limit,omitempty"`
+}
+
+type InterLibraryLoansQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=requested shipped received issued returning returned rejected cancelled"`
+}
+
+type InterLibraryLoanResponse struct {
+	RequiredResponseFields
+	Loan *model.InterLibraryLoan `json:"loan,omitempty"`
+}
+
+type InterLibraryLoansResponse struct {
+	RequiredResponseFields
+	Loans *[]model.InterLibraryLoan `json:"loans,omitempty"`
+}
+
+type BranchesResponse struct {
+	RequiredResponseFields
+	Branches *[]model.Branch `json:"branches,omitempty"`
+}
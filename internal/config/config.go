@@ -2,8 +2,12 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"library-management/backend/internal/api/handler"
 	"library-management/backend/internal/database/repository"
+	"library-management/backend/internal/notification"
+	"library-management/backend/internal/stream"
+	"library-management/backend/internal/util/token"
 	"os"
 	"time"
 
@@ -11,10 +15,12 @@ import (
 )
 
 type Config struct {
-	Env    string
-	Server ServerConfig
-	DB     DbConfig
-	JWT    JWTConfig
+	Env     string
+	Server  ServerConfig
+	DB      DbConfig
+	JWT     JWTConfig
+	Library LibraryConfig
+	Mail    MailConfig
 }
 type ServerConfig struct {
 	Port string
@@ -23,8 +29,28 @@ type DbConfig struct {
 	DSN string
 }
 type JWTConfig struct {
-	SecretKey           string
-	AccessTokenDuration time.Duration
+	TokenType            string
+	SecretKey            string
+	PrivateKey           string
+	SigningKeysDir       string
+	ActiveKeyID          string
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
+}
+
+type LibraryConfig struct {
+	HoldPickupWindow time.Duration
+	RequestExpiry    time.Duration
+	DueReminder      time.Duration
+}
+
+type MailConfig struct {
+	Transport    string
+	SMTPAddr     string
+	From         string
+	SMTPUsername string
+	SMTPPassword string
+	FilePath     string
 }
 
 func NewConfig() *Config {
@@ -35,19 +61,85 @@ func (cfg *Config) ParseFlag() error {
 	flag.StringVar(&cfg.Server.Port, "port", os.Getenv("PORT"), "API server port")
 	flag.StringVar(&cfg.Env, "env", os.Getenv("ENVIRONMENT"), "Environment(dev|prod)")
 	flag.StringVar(&cfg.DB.DSN, "db-dsn", os.Getenv("DATA_SOURCE_NAME"), "PostgreSQL DSN")
+	flag.StringVar(&cfg.JWT.TokenType, "token-type", os.Getenv("TOKEN_TYPE"), "Token Type(jwt|jwt-asymmetric|paseto-local|paseto-public)")
 	flag.StringVar(&cfg.JWT.SecretKey, "jwt-secret", os.Getenv("JWT_SECRET_KEY"), "JWT Secret Key")
+	flag.StringVar(&cfg.JWT.PrivateKey, "token-private-key", os.Getenv("TOKEN_PRIVATE_KEY"), "Hex encoded Ed25519 private key for paseto-public tokens")
+	flag.StringVar(&cfg.JWT.SigningKeysDir, "token-signing-keys-dir", os.Getenv("TOKEN_SIGNING_KEYS_DIR"), "Directory of <kid>.pem signing keys for jwt-asymmetric tokens")
+	flag.StringVar(&cfg.JWT.ActiveKeyID, "token-active-key-id", os.Getenv("TOKEN_ACTIVE_KEY_ID"), "Key ID used to sign new jwt-asymmetric tokens")
 	duration, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_DURATION"))
 	if err != nil {
 		return err
 	}
 	flag.DurationVar(&cfg.JWT.AccessTokenDuration, "jwt-access-token-duration", duration, "Access Token Duration")
+	refreshDuration, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_DURATION"))
+	if err != nil {
+		return err
+	}
+	flag.DurationVar(&cfg.JWT.RefreshTokenDuration, "jwt-refresh-token-duration", refreshDuration, "Refresh Token Duration")
+	holdPickupWindow, err := time.ParseDuration(os.Getenv("HOLD_PICKUP_WINDOW"))
+	if err != nil {
+		return err
+	}
+	flag.DurationVar(&cfg.Library.HoldPickupWindow, "hold-pickup-window", holdPickupWindow, "Time a reader has to collect a book held for them")
+	requestExpiry, err := time.ParseDuration(os.Getenv("REQUEST_EXPIRY"))
+	if err != nil {
+		return err
+	}
+	flag.DurationVar(&cfg.Library.RequestExpiry, "request-expiry", requestExpiry, "Time after which unapproved requests expire")
+	dueReminder, err := time.ParseDuration(os.Getenv("DUE_REMINDER"))
+	if err != nil {
+		return err
+	}
+	flag.DurationVar(&cfg.Library.DueReminder, "due-reminder", dueReminder, "How long before the due date readers are reminded of a loan")
+	flag.StringVar(&cfg.Mail.Transport, "mail-transport", os.Getenv("MAIL_TRANSPORT"), "Mail Transport(log|file|smtp)")
+	flag.StringVar(&cfg.Mail.SMTPAddr, "smtp-addr", os.Getenv("SMTP_ADDR"), "SMTP server host:port")
+	flag.StringVar(&cfg.Mail.From, "mail-from", os.Getenv("MAIL_FROM"), "From address of outgoing emails")
+	flag.StringVar(&cfg.Mail.SMTPUsername, "smtp-username", os.Getenv("SMTP_USERNAME"), "SMTP username, empty to skip authentication")
+	flag.StringVar(&cfg.Mail.SMTPPassword, "smtp-password", os.Getenv("SMTP_PASSWORD"), "SMTP password")
+	flag.StringVar(&cfg.Mail.FilePath, "mail-file", os.Getenv("MAIL_FILE"), "File the file mail transport appends emails to")
 	return nil
 }
 
-func (cfg *Config) InitHandler(r *repository.Repository) *handler.Handler {
-	return handler.NewHandler(r.AuthRepository, r.OwnerRepository, r.AdminRepository, r.ReaderRepository, r.SharedRepository)
+func (cfg *Config) InitHandler(r *repository.Repository, broker *stream.Broker, tokenMaker token.Maker) *handler.Handler {
+	return handler.NewHandler(r.AuthRepository, r.OwnerRepository, r.AdminRepository, r.ReaderRepository, r.SharedRepository, r.JobRepository, broker, tokenMaker, cfg.JWT.AccessTokenDuration, cfg.JWT.RefreshTokenDuration)
+}
+
+func (cfg *Config) InitTokenMaker() (token.Maker, error) {
+	switch cfg.JWT.TokenType {
+	case "", "jwt":
+		return token.NewJWTMaker(cfg.JWT.SecretKey)
+	case "jwt-asymmetric":
+		keys, err := token.LoadSigningKeys(cfg.JWT.SigningKeysDir)
+		if err != nil {
+			return nil, err
+		}
+		return token.NewAsymmetricJWTMaker(keys, cfg.JWT.ActiveKeyID)
+	case "paseto-local":
+		return token.NewPasetoLocalMaker(cfg.JWT.SecretKey)
+	case "paseto-public":
+		return token.NewPasetoPublicMaker(cfg.JWT.PrivateKey)
+	default:
+		return nil, fmt.Errorf("unsupported token type %s", cfg.JWT.TokenType)
+	}
+}
+
+func (cfg *Config) InitMailTransport() (notification.Transport, error) {
+	switch cfg.Mail.Transport {
+	case "", "log":
+		return notification.NewLogTransport(), nil
+	case "file":
+		return notification.NewFileTransport(cfg.Mail.FilePath)
+	case "smtp":
+		return notification.NewSMTPTransport(cfg.Mail.SMTPAddr, cfg.Mail.From, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword)
+	default:
+		return nil, fmt.Errorf("unsupported mail transport %s", cfg.Mail.Transport)
+	}
+}
+
+func (cfg *Config) InitBroker() *stream.Broker {
+	return stream.NewBroker(cfg.DB.DSN)
 }
 
 func (cfg *Config) InitRepository(db *gorm.DB) *repository.Repository {
-	return repository.NewRepository(db)
+	return repository.NewRepository(db, cfg.Library.HoldPickupWindow)
 }
@@ -7,7 +7,19 @@ var SampleEnv = Config{
 	Server: ServerConfig{Port: ":8081"},
 	DB:     DbConfig{DSN: "host=localhost user=postgres password=postgres dbname=library port=5433 sslmode=disable"},
 	JWT: JWTConfig{
-		SecretKey:           "bgeab3wbna3gh3p83hw8hgf83hg8hp8ghp8g38w8h3",
-		AccessTokenDuration: 30 * time.Minute,
+		TokenType:            "jwt",
+		SecretKey:            "bgeab3wbna3gh3p83hw8hgf83hg8hp8ghp8g38w8h3",
+		AccessTokenDuration:  30 * time.Minute,
+		RefreshTokenDuration: 7 * 24 * time.Hour,
+	},
+	Library: LibraryConfig{
+		HoldPickupWindow: 72 * time.Hour,
+		RequestExpiry:    14 * 24 * time.Hour,
+		DueReminder:      48 * time.Hour,
+	},
+	Mail: MailConfig{
+		Transport: "smtp",
+		SMTPAddr:  "localhost:1025",
+		From:      "library@example.com",
 	},
 }
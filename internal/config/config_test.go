@@ -1,7 +1,10 @@
 package config
 
 import (
+	"library-management/backend/internal/notification"
+	"library-management/backend/internal/util/token"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,10 +19,26 @@ func TestParseFlag(t *testing.T) {
 	assert.NoError(t, err)
 	err = os.Setenv("DATA_SOURCE_NAME", sampleEnv.DB.DSN)
 	assert.NoError(t, err)
+	err = os.Setenv("TOKEN_TYPE", sampleEnv.JWT.TokenType)
+	assert.NoError(t, err)
 	err = os.Setenv("JWT_SECRET_KEY", sampleEnv.JWT.SecretKey)
 	assert.NoError(t, err)
 	err = os.Setenv("ACCESS_TOKEN_DURATION", sampleEnv.JWT.AccessTokenDuration.String())
 	assert.NoError(t, err)
+	err = os.Setenv("REFRESH_TOKEN_DURATION", sampleEnv.JWT.RefreshTokenDuration.String())
+	assert.NoError(t, err)
+	err = os.Setenv("HOLD_PICKUP_WINDOW", sampleEnv.Library.HoldPickupWindow.String())
+	assert.NoError(t, err)
+	err = os.Setenv("REQUEST_EXPIRY", sampleEnv.Library.RequestExpiry.String())
+	assert.NoError(t, err)
+	err = os.Setenv("DUE_REMINDER", sampleEnv.Library.DueReminder.String())
+	assert.NoError(t, err)
+	err = os.Setenv("MAIL_TRANSPORT", sampleEnv.Mail.Transport)
+	assert.NoError(t, err)
+	err = os.Setenv("SMTP_ADDR", sampleEnv.Mail.SMTPAddr)
+	assert.NoError(t, err)
+	err = os.Setenv("MAIL_FROM", sampleEnv.Mail.From)
+	assert.NoError(t, err)
 
 	cfg := *NewConfig()
 	err = cfg.ParseFlag()
@@ -28,6 +47,114 @@ func TestParseFlag(t *testing.T) {
 	assert.Equal(t, sampleEnv.Server.Port, cfg.Server.Port)
 	assert.Equal(t, sampleEnv.Env, cfg.Env)
 	assert.Equal(t, sampleEnv.DB.DSN, cfg.DB.DSN)
+	assert.Equal(t, sampleEnv.JWT.TokenType, cfg.JWT.TokenType)
 	assert.Equal(t, sampleEnv.JWT.SecretKey, cfg.JWT.SecretKey)
 	assert.Equal(t, sampleEnv.JWT.AccessTokenDuration, cfg.JWT.AccessTokenDuration)
+	assert.Equal(t, sampleEnv.JWT.RefreshTokenDuration, cfg.JWT.RefreshTokenDuration)
+	assert.Equal(t, sampleEnv.Library.HoldPickupWindow, cfg.Library.HoldPickupWindow)
+	assert.Equal(t, sampleEnv.Library.RequestExpiry, cfg.Library.RequestExpiry)
+	assert.Equal(t, sampleEnv.Library.DueReminder, cfg.Library.DueReminder)
+	assert.Equal(t, sampleEnv.Mail, cfg.Mail)
+}
+
+func TestInitTokenMaker(t *testing.T) {
+	testCases := []struct {
+		name    string
+		jwt     JWTConfig
+		want    token.Maker
+		wantErr bool
+	}{
+		{
+			name: "DefaultJWT",
+			jwt:  JWTConfig{SecretKey: SampleEnv.JWT.SecretKey},
+			want: &token.JWTMaker{},
+		},
+		{
+			name: "PasetoLocal",
+			jwt:  JWTConfig{TokenType: "paseto-local", SecretKey: "bgeab3wbna3gh3p83hw8hgf83hg8hp8g"},
+			want: &token.PasetoLocalMaker{},
+		},
+		{
+			name: "PasetoPublic",
+			jwt:  JWTConfig{TokenType: "paseto-public", PrivateKey: "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"},
+			want: &token.PasetoPublicMaker{},
+		},
+		{
+			name:    "AsymmetricWithoutKeys",
+			jwt:     JWTConfig{TokenType: "jwt-asymmetric", SigningKeysDir: t.TempDir(), ActiveKeyID: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported",
+			jwt:     JWTConfig{TokenType: "saml", SecretKey: SampleEnv.JWT.SecretKey},
+			wantErr: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{JWT: tc.jwt}
+			maker, err := cfg.InitTokenMaker()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.IsType(t, tc.want, maker)
+		})
+	}
+}
+
+func TestInitMailTransport(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mail    MailConfig
+		want    notification.Transport
+		wantErr bool
+	}{
+		{
+			name: "DefaultLog",
+			mail: MailConfig{},
+			want: &notification.WriterTransport{},
+		},
+		{
+			name: "File",
+			mail: MailConfig{Transport: "file", FilePath: filepath.Join(t.TempDir(), "mail.log")},
+			want: &notification.WriterTransport{},
+		},
+		{
+			name: "SMTP",
+			mail: SampleEnv.Mail,
+			want: &notification.SMTPTransport{},
+		},
+		{
+			name:    "SMTPWithoutAddr",
+			mail:    MailConfig{Transport: "smtp", From: SampleEnv.Mail.From},
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported",
+			mail:    MailConfig{Transport: "carrier-pigeon"},
+			wantErr: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{Mail: tc.mail}
+			transport, err := cfg.InitMailTransport()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.IsType(t, tc.want, transport)
+		})
+	}
 }
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"fmt"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/database/repository"
+	"library-management/backend/internal/util"
+
+	"gorm.io/gorm"
+)
+
+// Migrate brings the database schema up to date with the models
+func Migrate(db *gorm.DB) error {
+	err := migrateBookInventoryKey(db)
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&model.Library{}, &model.Branch{}, &model.LibraryPolicy{}, &model.MembershipTier{}, &model.Users{}, &model.BookInventory{}, &model.BookCopy{}, &model.RequestEvents{}, &model.RequestStatusChange{}, &model.IssueRegistry{}, &model.Hold{}, &model.FineLedgerEntry{}, &model.InterLibraryLoan{}, &model.ScheduledJob{}, &model.Notification{}, &model.OutboxEmail{}, &model.RefreshToken{})
+	if err != nil {
+		return err
+	}
+
+	err = backfillBookLibraryIDs(db)
+	if err != nil {
+		return err
+	}
+
+	err = backfillLibraryPolicies(db)
+	if err != nil {
+		return err
+	}
+
+	err = backfillRequestStatus(db)
+	if err != nil {
+		return err
+	}
+
+	err = backfillRequestStatusChanges(db)
+	if err != nil {
+		return err
+	}
+
+	err = backfillCardNumbers(db)
+	if err != nil {
+		return err
+	}
+
+	return backfillBranches(db)
+}
+
+// migrateBookInventoryKey replaces the ISBN-only primary key of
+// book_inventories with (isbn, lib_id) so the same title can be stocked by
+// several libraries. Foreign keys pointing at the old key are dropped and
+// recreated as composite keys by AutoMigrate.
+func migrateBookInventoryKey(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&model.BookInventory{}) {
+		return nil
+	}
+
+	var keyColumns int64
+	query := `SELECT COUNT(*) FROM information_schema.table_constraints tc
+						JOIN information_schema.key_column_usage kcu
+							ON kcu.constraint_name = tc.constraint_name AND kcu.table_name = tc.table_name
+						WHERE tc.table_name = 'book_inventories' AND tc.constraint_type = 'PRIMARY KEY'`
+	if err := db.Raw(query).Scan(&keyColumns).Error; err != nil {
+		return err
+	}
+	if keyColumns != 1 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE book_inventories DROP CONSTRAINT book_inventories_pkey CASCADE`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`ALTER TABLE book_inventories ADD PRIMARY KEY (isbn, lib_id)`).Error
+	})
+}
+
+// backfillBookLibraryIDs sets the library of rows that referenced a book by
+// ISBN alone, which was unambiguous before ISBNs were scoped per library
+func backfillBookLibraryIDs(db *gorm.DB) error {
+	for _, table := range []string{"book_copies", "request_events", "issue_registries"} {
+		query := `UPDATE ` + table + ` t SET lib_id = b.lib_id
+							FROM book_inventories b
+							WHERE t.book_id = b.isbn AND (t.lib_id IS NULL OR t.lib_id = '')`
+		if err := db.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillLibraryPolicies gives every library a policy row, carrying over the
+// renewal settings that were stored on the library before policies existed
+func backfillLibraryPolicies(db *gorm.DB) error {
+	maxRenewals, autoApproveRenewals := fmt.Sprint(util.DefaultMaxRenewals), "false"
+	legacyColumns := db.Migrator().HasColumn(&model.Library{}, "max_renewals") && db.Migrator().HasColumn(&model.Library{}, "auto_approve_renewals")
+	if legacyColumns {
+		maxRenewals, autoApproveRenewals = "COALESCE(l.max_renewals, "+maxRenewals+")", "COALESCE(l.auto_approve_renewals, false)"
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		query := `INSERT INTO library_policies (lib_id, loan_period_days, max_concurrent_loans, max_renewals, auto_approve_renewals, daily_fine_cents, fine_cap_cents, max_balance_cents)
+							SELECT l.id, ?, ?, ` + maxRenewals + `, ` + autoApproveRenewals + `, 0, 0, ?
+							FROM libraries l
+							WHERE NOT EXISTS (SELECT 1 FROM library_policies p WHERE p.lib_id = l.id)`
+		if err := tx.Exec(query, util.DefaultLoanPeriodDays, util.DefaultMaxConcurrentLoans, util.DefaultMaxBalanceCents).Error; err != nil {
+			return err
+		}
+
+		if !legacyColumns {
+			return nil
+		}
+		if err := tx.Migrator().DropColumn(&model.Library{}, "max_renewals"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&model.Library{}, "auto_approve_renewals")
+	})
+}
+
+// backfillRequestStatus marks requests approved before request statuses
+// existed. Rejected and expired requests used to be deleted, so every other
+// request is still pending.
+func backfillRequestStatus(db *gorm.DB) error {
+	return db.Model(&model.RequestEvents{}).
+		Where("approval_date IS NOT NULL").
+		Where("status = ?", util.RequestStatusPending).
+		Update("status", util.RequestStatusApproved).Error
+}
+
+// backfillRequestStatusChanges gives requests raised before status history
+// was kept the transitions that can be reconstructed from the request row
+func backfillRequestStatusChanges(db *gorm.DB) error {
+	created := `INSERT INTO request_status_changes (change_id, req_id, from_status, to_status, actor_id, reason, changed_date)
+							SELECT r.req_id || ':pending', r.req_id, '', 'pending', r.reader_id, '', r.request_date
+							FROM request_events r
+							WHERE NOT EXISTS (SELECT 1 FROM request_status_changes c WHERE c.req_id = r.req_id)`
+	if err := db.Exec(created).Error; err != nil {
+		return err
+	}
+
+	approved := `INSERT INTO request_status_changes (change_id, req_id, from_status, to_status, actor_id, reason, changed_date)
+							SELECT r.req_id || ':approved', r.req_id, 'pending', 'approved', r.approver_id, '', r.approval_date
+							FROM request_events r
+							WHERE r.status = 'approved' AND r.approval_date IS NOT NULL
+								AND NOT EXISTS (SELECT 1 FROM request_status_changes c WHERE c.req_id = r.req_id AND c.to_status = 'approved')`
+	return db.Exec(approved).Error
+}
+
+// backfillCardNumbers gives a library card number to readers who signed up
+// before card numbers were introduced
+func backfillCardNumbers(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var readerIDs []string
+		if err := tx.Model(&model.Users{}).Where("role = ?", util.ReaderRole).Where("card_number IS NULL").Pluck("id", &readerIDs).Error; err != nil {
+			return err
+		}
+
+		for _, readerID := range readerIDs {
+			cardNumber, err := repository.NewCardNumber(tx)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&model.Users{}).Where("id = ?", readerID).Update("card_number", cardNumber).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backfillBranches gives libraries created before branches existed a main
+// branch, holding all of their copies and staffed by all of their admins
+func backfillBranches(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var libIDs []string
+		if err := tx.Model(&model.Library{}).Where("NOT EXISTS (SELECT 1 FROM branches b WHERE b.lib_id = libraries.id)").Pluck("id", &libIDs).Error; err != nil {
+			return err
+		}
+
+		for _, libID := range libIDs {
+			branch := model.Branch{
+				BranchID: util.RandomUUID(),
+				LibID:    libID,
+				Name:     util.MainBranchName,
+			}
+			if err := tx.Create(&branch).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&model.BookCopy{}).Where("lib_id = ?", libID).Where("branch_id IS NULL").Update("branch_id", branch.BranchID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.Users{}).Where("lib_id = ?", libID).Where("role = ?", util.AdminRole).Where("branch_id IS NULL").Update("branch_id", branch.BranchID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/database/transaction"
 	"library-management/backend/internal/util"
@@ -23,19 +24,21 @@ type AdminRepositoryInterface interface {
 }
 
 type AdminRepository struct {
-	db        *gorm.DB
-	txManager *transaction.TxManager
-	mu        sync.RWMutex
+	db               *gorm.DB
+	txManager        *transaction.TxManager
+	mu               sync.RWMutex
+	holdPickupWindow time.Duration
 }
 
-func NewAdminRepository(db *gorm.DB, txManager *transaction.TxManager) *AdminRepository {
+func NewAdminRepository(db *gorm.DB, txManager *transaction.TxManager, holdPickupWindow time.Duration) *AdminRepository {
 	return &AdminRepository{
-		db:        db,
-		txManager: txManager,
+		db:               db,
+		txManager:        txManager,
+		holdPickupWindow: holdPickupWindow,
 	}
 }
 
-func (admin *AdminRepository) AddBook(ctx context.Context, book *model.BookInventory, email string) error {
+func (admin *AdminRepository) AddBook(ctx context.Context, book *model.BookInventory, bookCopy *model.BookCopy, email string) error {
 	admin.mu.Lock()
 	defer admin.mu.Unlock()
 
@@ -54,24 +57,46 @@ func (admin *AdminRepository) AddBook(ctx context.Context, book *model.BookInven
 		log.Println(book)
 
 		var existingBook model.BookInventory
-		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", book.ISBN).First(&existingBook)
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", book.ISBN).Where("lib_id = ?", user.LibID).First(&existingBook)
 		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return result.Error
 		}
 
-		if result.RowsAffected > 0 {
-			if *existingBook.LibID != *user.LibID {
-				return errors.New("book with same ISBN already exists in another library")
+		if result.RowsAffected == 0 {
+			log.Print(*book)
+			if err := tx.Create(book).Error; err != nil {
+				return err
 			}
-			return tx.Model(&model.BookInventory{}).Where("isbn = ?", existingBook.ISBN).Update("total_copies", existingBook.TotalCopies+1).Update("available_copies", existingBook.AvailableCopies+1).Error
 		}
 
-		log.Print(*book)
-		return tx.Create(book).Error
+		var existingCopy model.BookCopy
+		result = tx.Where("barcode = ?", bookCopy.Barcode).First(&existingCopy)
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return errors.New("copy with same barcode already exists")
+		}
+
+		bookCopy.BookID = book.ISBN
+		bookCopy.LibID = *user.LibID
+		if bookCopy.BranchID == nil {
+			bookCopy.BranchID = user.BranchID
+		} else if err := libraryBranch(tx, *user.LibID, *bookCopy.BranchID, &model.Branch{}); err != nil {
+			return err
+		}
+		if err := tx.Create(bookCopy).Error; err != nil {
+			return err
+		}
+
+		if err := promoteHolds(tx, book.ISBN, *user.LibID, admin.holdPickupWindow); err != nil {
+			return err
+		}
+		return syncCopyCounts(tx, book.ISBN, *user.LibID)
 	})
 }
 
-func (admin *AdminRepository) RemoveBook(ctx context.Context, isbn string, userID string) error {
+func (admin *AdminRepository) RemoveBook(ctx context.Context, isbn string, barcode string, userID string) error {
 	admin.mu.Lock()
 	defer admin.mu.Unlock()
 
@@ -92,20 +117,38 @@ func (admin *AdminRepository) RemoveBook(ctx context.Context, isbn string, userI
 			return result.Error
 		}
 
-		if (existingBook.AvailableCopies == 1) && (existingBook.TotalCopies == 1) {
-			return tx.Model(&model.BookInventory{}).
-				Where("isbn = ?", existingBook.ISBN).
-				Delete(&existingBook).Error
+		var bookCopy model.BookCopy
+		query := tx.Set("gorm:query_option", "FOR UPDATE").Where("book_id = ?", existingBook.ISBN).Where("lib_id = ?", existingBook.LibID)
+		if barcode != "" {
+			query = query.Where("barcode = ?", barcode)
+		} else {
+			query = query.Where("status IN ?", []string{util.CopyStatusAvailable, util.CopyStatusDamaged}).Order("status DESC, barcode")
+		}
+		result = query.First(&bookCopy)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				if barcode != "" {
+					return errors.New("copy with supplied barcode not found for book")
+				}
+				return errors.New("cannot remove issued books")
+			}
+			return result.Error
+		}
+
+		switch bookCopy.Status {
+		case util.CopyStatusIssued:
+			return errors.New("cannot remove issued books")
+		case util.CopyStatusOnHold:
+			return errors.New("cannot remove a copy reserved for a hold")
+		case util.CopyStatusWithdrawn:
+			return errors.New("copy has already been removed")
 		}
 
-		if existingBook.AvailableCopies > 0 {
-			return tx.Model(&model.BookInventory{}).
-				Where("isbn = ?", existingBook.ISBN).
-				Update("total_copies", existingBook.TotalCopies-1).
-				Update("available_copies", existingBook.AvailableCopies-1).Error
+		if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", util.CopyStatusWithdrawn).Error; err != nil {
+			return err
 		}
 
-		return errors.New("cannot remove issued books")
+		return syncCopyCounts(tx, existingBook.ISBN, *existingBook.LibID)
 	})
 }
 
@@ -129,8 +172,27 @@ func (admin *AdminRepository) UpdateBook(ctx context.Context, isbn string, title
 			return result.Error
 		}
 
-		query := `update book_inventories set title = ?, authors = ?, publisher = ?, version = ? where isbn = ?`
-		return tx.Exec(query, title, authors, publisher, version, isbn).Error
+		query := `update book_inventories set title = ?, authors = ?, publisher = ?, version = ? where isbn = ? and lib_id = ?`
+		return tx.Exec(query, title, authors, publisher, version, isbn, user.LibID).Error
+	})
+}
+
+// GetAdminLibrary looks up the library an admin works at
+func (admin *AdminRepository) GetAdminLibrary(ctx context.Context, adminID string, libID *string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var user model.Users
+		if err := tx.Where("id = ?", adminID).First(&user).Error; err != nil {
+			return err
+		}
+		if user.Role != util.AdminRole || user.LibID == nil {
+			return errors.New("admin is not assigned to a library")
+		}
+
+		*libID = *user.LibID
+		return nil
 	})
 }
 
@@ -147,7 +209,7 @@ func (admin *AdminRepository) ListIssueRequests(ctx context.Context, requestDeta
 		lib_id := admin.LibID
 		log.Print(lib_id)
 		query := `SELECT r.*, b.title as book_title, b.available_copies FROM request_events r, book_inventories b
-              WHERE r.book_id = b.isbn AND r.approver_id IS NULL AND b.lib_id = '` + *lib_id + `'`
+              WHERE r.book_id = b.isbn AND r.lib_id = b.lib_id AND r.status = 'pending' AND b.lib_id = '` + *lib_id + `'`
 		return tx.Set("gorm:query_option", "FOR SHARE").
 			Raw(query).
 			Scan(requestDetails).
@@ -155,63 +217,85 @@ func (admin *AdminRepository) ListIssueRequests(ctx context.Context, requestDeta
 	})
 }
 
-func (admin *AdminRepository) ApproveIssueRequest(ctx context.Context, requestID string, approverID string) error {
+func (admin *AdminRepository) ApproveIssueRequest(ctx context.Context, requestID string, approverID string, barcode string) error {
 	admin.mu.Lock()
 	defer admin.mu.Unlock()
 
 	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", approverID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
 		var existingIssueRequest model.RequestEvents
-		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).First(&existingIssueRequest).Error; err != nil {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).First(&existingIssueRequest).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return errors.New("invalid Issue Request ID")
 			}
 			return err
 		}
 
-		var bookInventory model.BookInventory
-		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", existingIssueRequest.BookID).First(&bookInventory).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("invalid ISBN in issue request")
-			}
+		if existingIssueRequest.RequestType == util.ReturnRequestType || existingIssueRequest.RequestType == util.RenewRequestType {
+			return errors.New("invalid Issue Request ID")
+		}
+		if existingIssueRequest.ApproverID != nil {
+			return errors.New("issue request already approved")
+		}
+		if existingIssueRequest.Status != util.RequestStatusPending {
+			return errors.New("issue request is no longer pending")
+		}
+
+		var policy model.LibraryPolicy
+		if err := readerPolicy(tx, existingIssueRequest.LibID, existingIssueRequest.ReaderID, &policy); err != nil {
+			return err
+		}
+		if err := checkLoanLimit(tx, existingIssueRequest.ReaderID, existingIssueRequest.LibID, &policy, existingIssueRequest.ReqID); err != nil {
 			return err
 		}
 
-		if bookInventory.AvailableCopies < 1 {
-			return errors.New("no available copies in inventory")
+		branchID, err := staffBranch(tx, approverID)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Model(&model.BookInventory{}).Where("isbn = ?", bookInventory.ISBN).Update("available_copies", bookInventory.AvailableCopies-1).Error; err != nil {
+		var issue model.IssueRegistry
+		if err := issueCopy(tx, existingIssueRequest.ReaderID, existingIssueRequest.BookID, existingIssueRequest.LibID, barcode, approverID, branchID, &policy, &issue); err != nil {
 			return err
 		}
 
-		approvalDate := time.Now()
-		expectedReturnDate := approvalDate.Add(time.Hour * 24 * 7)
-		if err := tx.Model(&model.RequestEvents{}).Where("req_id = ?", requestID).Update("approver_id", approverID).Update("approval_date", approvalDate.Format(time.RFC3339)).Error; err != nil {
+		if err := transitionRequest(tx, &existingIssueRequest, util.RequestStatusApproved, &approverID, "", map[string]interface{}{
+			"approval_date": issue.IssueDate,
+			"approver_id":   approverID,
+		}); err != nil {
 			return err
 		}
 
-		issueRegister := model.IssueRegistry{
-			IssueID:            util.RandomUUID(),
-			BookID:             existingIssueRequest.BookID,
-			ReaderID:           existingIssueRequest.ReaderID,
-			IssueApproverID:    approverID,
-			IssueStatus:        "open",
-			IssueDate:          approvalDate.Format(time.RFC3339),
-			ExpectedReturnDate: expectedReturnDate.Format(time.RFC3339),
-			ReturnDate:         nil,
-			ReturnApproverID:   nil,
+		dueDate, err := time.Parse(time.RFC3339, issue.ExpectedReturnDate)
+		if err != nil {
+			return err
 		}
-		return tx.Model(&model.IssueRegistry{}).Create(issueRegister).Error
+		if err := notifyReader(tx, existingIssueRequest.ReaderID, util.NotificationRequestApproved, existingIssueRequest.BookID, existingIssueRequest.LibID, &requestID, map[string]string{
+			"request_type": util.IssueRequestType,
+			"due_date":     dueDate.Format(util.EmailDateFormat),
+		}); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventApproved, &existingIssueRequest)
 	})
 }
 
-func (admin *AdminRepository) RejectIssueRequest(ctx context.Context, requestID string) error {
+func (admin *AdminRepository) RejectIssueRequest(ctx context.Context, requestID string, adminID string, reason string) error {
 	admin.mu.Lock()
 	defer admin.mu.Unlock()
 
 	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
 		var existingIssueRequest model.RequestEvents
-		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).First(&existingIssueRequest)
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).First(&existingIssueRequest)
 
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -220,6 +304,1547 @@ func (admin *AdminRepository) RejectIssueRequest(ctx context.Context, requestID
 			return result.Error
 		}
 
-		return tx.Model(&model.RequestEvents{}).Where("req_id = ?", requestID).Delete(&existingIssueRequest).Error
+		if existingIssueRequest.RequestType == util.ReturnRequestType || existingIssueRequest.RequestType == util.RenewRequestType {
+			return errors.New("invalid Issue Request ID")
+		}
+		if existingIssueRequest.Status != util.RequestStatusPending {
+			return errors.New("issue request is no longer pending")
+		}
+
+		if err := notifyReader(tx, existingIssueRequest.ReaderID, util.NotificationRequestRejected, existingIssueRequest.BookID, existingIssueRequest.LibID, &requestID, map[string]string{
+			"request_type": util.IssueRequestType,
+		}); err != nil {
+			return err
+		}
+		if err := publishRequestEvent(tx, util.RequestEventRejected, &existingIssueRequest); err != nil {
+			return err
+		}
+
+		return transitionRequest(tx, &existingIssueRequest, util.RequestStatusRejected, &adminID, reason, nil)
+	})
+}
+
+func (admin *AdminRepository) ApproveReturnRequest(ctx context.Context, requestID string, approverID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", approverID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var existingReturnRequest model.RequestEvents
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).Where("request_type = ?", util.ReturnRequestType).First(&existingReturnRequest).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid Return Request ID")
+			}
+			return err
+		}
+
+		if existingReturnRequest.ApproverID != nil {
+			return errors.New("return request already approved")
+		}
+		if existingReturnRequest.Status != util.RequestStatusPending {
+			return errors.New("return request is no longer pending")
+		}
+
+		var existingIssue model.IssueRegistry
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("reader_id = ?", existingReturnRequest.ReaderID).Where("book_id = ?", existingReturnRequest.BookID).Where("lib_id = ?", existingReturnRequest.LibID).Where("issue_status = ?", util.IssueStatusOpen).First(&existingIssue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("no open issue found for return request")
+			}
+			return err
+		}
+
+		returnTime := time.Now()
+		if err := transitionRequest(tx, &existingReturnRequest, util.RequestStatusApproved, &approverID, "", map[string]interface{}{
+			"approval_date": returnTime.Format(time.RFC3339),
+			"approver_id":   approverID,
+		}); err != nil {
+			return err
+		}
+
+		branchID, err := staffBranch(tx, approverID)
+		if err != nil {
+			return err
+		}
+		if err := returnIssue(tx, &existingIssue, approverID, branchID, returnTime, admin.holdPickupWindow); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventApproved, &existingReturnRequest)
+	})
+}
+
+func (admin *AdminRepository) RejectReturnRequest(ctx context.Context, requestID string, adminID string, reason string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var existingReturnRequest model.RequestEvents
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).Where("request_type = ?", util.ReturnRequestType).First(&existingReturnRequest)
+
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("invalid Return Request ID")
+			}
+			return result.Error
+		}
+
+		if existingReturnRequest.ApproverID != nil {
+			return errors.New("return request already approved")
+		}
+		if existingReturnRequest.Status != util.RequestStatusPending {
+			return errors.New("return request is no longer pending")
+		}
+
+		if err := publishRequestEvent(tx, util.RequestEventRejected, &existingReturnRequest); err != nil {
+			return err
+		}
+		return transitionRequest(tx, &existingReturnRequest, util.RequestStatusRejected, &adminID, reason, nil)
+	})
+}
+
+func (admin *AdminRepository) ApproveRenewalRequest(ctx context.Context, requestID string, approverID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", approverID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var existingRenewalRequest model.RequestEvents
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).Where("request_type = ?", util.RenewRequestType).First(&existingRenewalRequest).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid Renewal Request ID")
+			}
+			return err
+		}
+
+		if existingRenewalRequest.ApprovalDate != nil {
+			return errors.New("renewal request already approved")
+		}
+		if existingRenewalRequest.Status != util.RequestStatusPending {
+			return errors.New("renewal request is no longer pending")
+		}
+
+		var existingIssue model.IssueRegistry
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("reader_id = ?", existingRenewalRequest.ReaderID).Where("book_id = ?", existingRenewalRequest.BookID).Where("lib_id = ?", existingRenewalRequest.LibID).Where("issue_status = ?", util.IssueStatusOpen).First(&existingIssue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("no open issue found for renewal request")
+			}
+			return err
+		}
+
+		var policy model.LibraryPolicy
+		if err := checkRenewal(tx, &existingIssue, &policy); err != nil {
+			return err
+		}
+
+		if err := renewIssue(tx, &existingIssue, &policy, &existingRenewalRequest, &approverID); err != nil {
+			return err
+		}
+		if err := notifyRenewal(tx, &existingIssue, requestID); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventApproved, &existingRenewalRequest)
+	})
+}
+
+func (admin *AdminRepository) RejectRenewalRequest(ctx context.Context, requestID string, adminID string, reason string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var existingRenewalRequest model.RequestEvents
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).Where("request_type = ?", util.RenewRequestType).First(&existingRenewalRequest).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid Renewal Request ID")
+			}
+			return err
+		}
+
+		if existingRenewalRequest.ApprovalDate != nil {
+			return errors.New("renewal request already approved")
+		}
+		if existingRenewalRequest.Status != util.RequestStatusPending {
+			return errors.New("renewal request is no longer pending")
+		}
+
+		if err := notifyReader(tx, existingRenewalRequest.ReaderID, util.NotificationRequestRejected, existingRenewalRequest.BookID, existingRenewalRequest.LibID, &requestID, map[string]string{
+			"request_type": util.RenewRequestType,
+		}); err != nil {
+			return err
+		}
+		if err := publishRequestEvent(tx, util.RequestEventRejected, &existingRenewalRequest); err != nil {
+			return err
+		}
+
+		return transitionRequest(tx, &existingRenewalRequest, util.RequestStatusRejected, &adminID, reason, nil)
+	})
+}
+
+func (admin *AdminRepository) ResetReaderPassword(ctx context.Context, email string, hashedPassword string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", adminID).First(&adminUser)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var reader model.Users
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("email = ?", email).Where("role = ?", util.ReaderRole).Where("lib_id = ?", adminUser.LibID).First(&reader)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("reader with supplied email not found in library")
+			}
+			return result.Error
+		}
+
+		if err := tx.Model(&model.Users{}).Where("id = ?", reader.ID).Update("hashed_password", hashedPassword).Error; err != nil {
+			return err
+		}
+		return revokeUserRefreshTokens(tx, reader.ID)
+	})
+}
+
+func (admin *AdminRepository) GetCopyByBarcode(ctx context.Context, barcode string, copyDetails *model.BookCopyDetails, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", adminID).First(&adminUser)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		query := `SELECT c.*, b.title as book_title, i.issue_id, i.reader_id, u.name as reader_name, i.issue_date, i.expected_return_date
+							FROM book_copies c
+							JOIN book_inventories b ON b.isbn = c.book_id AND b.lib_id = c.lib_id
+							LEFT JOIN issue_registries i ON i.copy_id = c.barcode AND i.issue_status = ?
+							LEFT JOIN users u ON u.id = i.reader_id
+							WHERE c.barcode = ? AND b.lib_id = ?`
+		result = tx.Raw(query, util.IssueStatusOpen, barcode, adminUser.LibID).Scan(copyDetails)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("copy with supplied barcode not found in library")
+		}
+		return nil
+	})
+}
+
+func (admin *AdminRepository) ListCopies(ctx context.Context, isbn string, copies *[]model.BookCopy, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", adminID).First(&adminUser)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var existingBook model.BookInventory
+		result = tx.Where("isbn = ?", isbn).Where("lib_id = ?", adminUser.LibID).First(&existingBook)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("book with supplied ISBN not found in database")
+			}
+			return result.Error
+		}
+
+		return tx.Model(&model.BookCopy{}).Where("book_id = ?", isbn).Where("lib_id = ?", adminUser.LibID).Order("barcode").Find(copies).Error
+	})
+}
+
+func (admin *AdminRepository) UpdateCopy(ctx context.Context, barcode string, condition *string, shelfLocation *string, status string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", adminID).First(&adminUser)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var bookCopy model.BookCopy
+		result = tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("barcode = ?", barcode).
+			Where("lib_id = ?", adminUser.LibID).
+			First(&bookCopy)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("copy with supplied barcode not found in library")
+			}
+			return result.Error
+		}
+
+		updates := make(map[string]interface{})
+		if condition != nil {
+			updates["condition"] = *condition
+		}
+		if shelfLocation != nil {
+			updates["shelf_location"] = *shelfLocation
+		}
+		if status != "" && status != bookCopy.Status {
+			if status != util.CopyStatusAvailable && status != util.CopyStatusDamaged {
+				return errors.New("copy status can only be set to available or damaged")
+			}
+			if bookCopy.Status != util.CopyStatusAvailable && bookCopy.Status != util.CopyStatusDamaged {
+				return errors.New("cannot change status of a copy that is " + bookCopy.Status)
+			}
+			updates["status"] = status
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", barcode).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := promoteHolds(tx, bookCopy.BookID, bookCopy.LibID, admin.holdPickupWindow); err != nil {
+			return err
+		}
+		return syncCopyCounts(tx, bookCopy.BookID, bookCopy.LibID)
+	})
+}
+
+// MarkOverdueIssues flags open loans that are past their due date
+func (admin *AdminRepository) MarkOverdueIssues(ctx context.Context) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var openIssues []model.IssueRegistry
+		if err := tx.Where("issue_status = ?", util.IssueStatusOpen).Where("overdue = ?", false).Find(&openIssues).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		overdueIDs := make([]string, 0)
+		for _, issue := range openIssues {
+			dueDate, err := time.Parse(time.RFC3339, issue.ExpectedReturnDate)
+			if err != nil || now.Before(dueDate) {
+				continue
+			}
+			overdueIDs = append(overdueIDs, issue.IssueID)
+
+			if err := notifyReader(tx, issue.ReaderID, util.NotificationOverdue, issue.BookID, issue.LibID, nil, map[string]string{
+				"due_date": dueDate.Format(util.EmailDateFormat),
+			}); err != nil {
+				return err
+			}
+		}
+		if len(overdueIDs) == 0 {
+			return nil
+		}
+
+		return tx.Model(&model.IssueRegistry{}).Where("issue_id IN ?", overdueIDs).Update("overdue", true).Error
+	})
+}
+
+// RemindDueSoon emails readers whose loans fall due within the given window.
+// Each loan is reminded once per due date; renewing it re-arms the reminder.
+func (admin *AdminRepository) RemindDueSoon(ctx context.Context, within time.Duration) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var openIssues []model.IssueRegistry
+		if err := tx.Where("issue_status = ?", util.IssueStatusOpen).Where("overdue = ?", false).Where("due_reminder_sent = ?", false).Find(&openIssues).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		remindedIDs := make([]string, 0)
+		for _, issue := range openIssues {
+			dueDate, err := time.Parse(time.RFC3339, issue.ExpectedReturnDate)
+			if err != nil || dueDate.Before(now) || dueDate.After(now.Add(within)) {
+				continue
+			}
+			remindedIDs = append(remindedIDs, issue.IssueID)
+
+			if err := notifyReader(tx, issue.ReaderID, util.NotificationDueSoon, issue.BookID, issue.LibID, nil, map[string]string{
+				"due_date": dueDate.Format(util.EmailDateFormat),
+			}); err != nil {
+				return err
+			}
+		}
+		if len(remindedIDs) == 0 {
+			return nil
+		}
+
+		return tx.Model(&model.IssueRegistry{}).Where("issue_id IN ?", remindedIDs).Update("due_reminder_sent", true).Error
+	})
+}
+
+// ExpireStaleRequests closes requests that were left pending for longer than
+// maxAge
+func (admin *AdminRepository) ExpireStaleRequests(ctx context.Context, maxAge time.Duration) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var pendingRequests []model.RequestEvents
+		if err := tx.Where("status = ?", util.RequestStatusPending).Find(&pendingRequests).Error; err != nil {
+			return err
+		}
+
+		cutoff := time.Now().Add(-maxAge)
+		reason := fmt.Sprintf("no decision within %s", maxAge)
+		for i := range pendingRequests {
+			requestDate, err := time.Parse(time.RFC3339, pendingRequests[i].RequestDate)
+			if err != nil || requestDate.After(cutoff) {
+				continue
+			}
+
+			if err := transitionRequest(tx, &pendingRequests[i], util.RequestStatusExpired, nil, reason, nil); err != nil {
+				return err
+			}
+			if err := publishRequestEvent(tx, util.RequestEventExpired, &pendingRequests[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AccrueOverdueFines charges readers for every open loan past its due date
+func (admin *AdminRepository) AccrueOverdueFines(ctx context.Context) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		now := time.Now()
+		var openIssues []model.IssueRegistry
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("issue_status = ?", util.IssueStatusOpen).Find(&openIssues).Error; err != nil {
+			return err
+		}
+
+		policies := make(map[string]*model.LibraryPolicy)
+		for i := range openIssues {
+			key := openIssues[i].LibID + ":" + openIssues[i].ReaderID
+			policy, ok := policies[key]
+			if !ok {
+				policy = &model.LibraryPolicy{}
+				if err := readerPolicy(tx, openIssues[i].LibID, openIssues[i].ReaderID, policy); err != nil {
+					return err
+				}
+				policies[key] = policy
+			}
+
+			if err := accrueOverdueFine(tx, &openIssues[i], policy, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordFineEntry adds a charge, payment or waiver to the ledger of a reader in
+// the admin's library. Payments and waivers are stored as negative amounts and
+// cannot exceed the outstanding balance.
+func (admin *AdminRepository) RecordFineEntry(ctx context.Context, entry *model.FineLedgerEntry, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", adminID).First(&adminUser)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var reader model.Users
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", entry.ReaderID).Where("role = ?", util.ReaderRole).Where("lib_id = ?", adminUser.LibID).First(&reader)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("reader not found in library")
+			}
+			return result.Error
+		}
+
+		if entry.AmountCents <= 0 {
+			return errors.New("amount must be greater than zero")
+		}
+
+		if entry.IssueID != nil {
+			var issue model.IssueRegistry
+			result = tx.Where("issue_id = ?", *entry.IssueID).Where("reader_id = ?", reader.ID).First(&issue)
+			if result.Error != nil {
+				if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+					return errors.New("issue not found for reader")
+				}
+				return result.Error
+			}
+		}
+
+		switch entry.EntryType {
+		case util.FineTypeLost, util.FineTypeDamaged:
+		case util.FineTypePayment, util.FineTypeWaiver:
+			balance, err := readerBalance(tx, reader.ID, *adminUser.LibID)
+			if err != nil {
+				return err
+			}
+			if entry.AmountCents > balance {
+				return errors.New("amount exceeds outstanding balance")
+			}
+			entry.AmountCents = -entry.AmountCents
+		default:
+			return errors.New("invalid fine entry type")
+		}
+
+		entry.EntryID = util.RandomUUID()
+		entry.LibID = *adminUser.LibID
+		entry.RecordedBy = &adminID
+		entry.EntryDate = time.Now().Format(time.RFC3339)
+		return tx.Create(entry).Error
+	})
+}
+
+func (admin *AdminRepository) GetReaderFines(ctx context.Context, readerID string, entries *[]model.FineLedgerEntry, balance *int64, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", adminID).First(&adminUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := libraryReader(tx, readerID, *adminUser.LibID); err != nil {
+			return err
+		}
+
+		return readerFines(tx, readerID, *adminUser.LibID, entries, balance)
+	})
+}
+
+func (admin *AdminRepository) GetReaderLoans(ctx context.Context, readerID string, loans *[]model.LoanDetails, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+		if err := libraryReader(tx, readerID, *adminUser.LibID); err != nil {
+			return err
+		}
+
+		return currentLoans(tx, readerID, *adminUser.LibID, loans)
+	})
+}
+
+func (admin *AdminRepository) GetReaderLoanHistory(ctx context.Context, readerID string, page int, pageSize int, loans *[]model.LoanDetails, total *int64, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+		if err := libraryReader(tx, readerID, *adminUser.LibID); err != nil {
+			return err
+		}
+
+		return loanHistory(tx, readerID, *adminUser.LibID, page, pageSize, loans, total)
+	})
+}
+
+func (admin *AdminRepository) GetReaderRequests(ctx context.Context, readerID string, status string, requests *[]model.RequestDetails, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+		if err := libraryReader(tx, readerID, *adminUser.LibID); err != nil {
+			return err
+		}
+
+		return readerRequests(tx, readerID, *adminUser.LibID, status, requests)
+	})
+}
+
+func (admin *AdminRepository) GetRequestHistory(ctx context.Context, requestID string, changes *[]model.RequestStatusChange, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var request model.RequestEvents
+		result := tx.Where("req_id = ?", requestID).Where("lib_id = ?", adminUser.LibID).Limit(1).Find(&request)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invalid Request ID")
+		}
+
+		return requestHistory(tx, requestID, changes)
+	})
+}
+
+func (admin *AdminRepository) AddTier(ctx context.Context, tier *model.MembershipTier, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var existingTier model.MembershipTier
+		result := tx.Where("lib_id = ?", adminUser.LibID).Where("name = ?", tier.Name).Limit(1).Find(&existingTier)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return errors.New("tier with supplied name already exists")
+		}
+
+		tier.TierID = util.RandomUUID()
+		tier.LibID = *adminUser.LibID
+		return tx.Create(tier).Error
+	})
+}
+
+func (admin *AdminRepository) ListTiers(ctx context.Context, tiers *[]model.MembershipTier, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("lib_id = ?", adminUser.LibID).Order("name").Find(tiers).Error
+	})
+}
+
+func (admin *AdminRepository) UpdateTier(ctx context.Context, tierID string, updates map[string]interface{}, tier *model.MembershipTier, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("tier_id = ?", tierID).Where("lib_id = ?", adminUser.LibID).Limit(1).Find(tier)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("tier not found in library")
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&model.MembershipTier{}).Where("tier_id = ?", tierID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("tier_id = ?", tierID).First(tier).Error
+	})
+}
+
+// RemoveTier deletes a tier of the admin's library. Readers assigned to it fall
+// back to the library policy.
+func (admin *AdminRepository) RemoveTier(ctx context.Context, tierID string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("tier_id = ?", tierID).Where("lib_id = ?", adminUser.LibID).Delete(&model.MembershipTier{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("tier not found in library")
+		}
+		return nil
+	})
+}
+
+// AssignTier sets the membership tier and expiry date of a reader in the
+// admin's library. A nil tier or expiry clears it, so renewing a membership is
+// assigning it again with a later expiry.
+func (admin *AdminRepository) AssignTier(ctx context.Context, readerID string, tierID *string, membershipExpiry *string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+		if err := libraryReader(tx, readerID, *adminUser.LibID); err != nil {
+			return err
+		}
+
+		if tierID != nil {
+			var tier model.MembershipTier
+			result := tx.Where("tier_id = ?", *tierID).Where("lib_id = ?", adminUser.LibID).Limit(1).Find(&tier)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("tier not found in library")
+			}
+		}
+
+		return tx.Model(&model.Users{}).Where("id = ?", readerID).Updates(map[string]interface{}{
+			"tier_id":           tierID,
+			"membership_expiry": membershipExpiry,
+		}).Error
+	})
+}
+
+// DeclareLoan closes an open loan of the admin's library as lost or damaged.
+// Overdue fines are settled up to now, the copy leaves circulation, pending
+// requests for the loan are cancelled and feeCents, when positive, is charged
+// to the reader.
+func (admin *AdminRepository) DeclareLoan(ctx context.Context, issueID string, disposition string, feeCents int64, note string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var copyStatus, fineType string
+		switch disposition {
+		case util.IssueStatusLost:
+			copyStatus, fineType = util.CopyStatusLost, util.FineTypeLost
+		case util.IssueStatusDamaged:
+			copyStatus, fineType = util.CopyStatusDamaged, util.FineTypeDamaged
+		default:
+			return errors.New("loan can only be declared lost or damaged")
+		}
+		if feeCents < 0 {
+			return errors.New("fee cannot be negative")
+		}
+
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var issue model.IssueRegistry
+		result := tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("issue_id = ?", issueID).
+			Where("lib_id = ?", adminUser.LibID).
+			Where("issue_status = ?", util.IssueStatusOpen).
+			Limit(1).
+			Find(&issue)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("open loan not found in library")
+		}
+
+		now := time.Now()
+		if err := accrueOverdueOnReturn(tx, &issue, now); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.IssueRegistry{}).Where("issue_id = ?", issue.IssueID).Updates(map[string]interface{}{
+			"issue_status":       disposition,
+			"return_date":        now.Format(time.RFC3339),
+			"return_approver_id": adminID,
+		}).Error; err != nil {
+			return err
+		}
+		if issue.CopyID != nil {
+			var bookCopy model.BookCopy
+			if err := tx.Where("barcode = ?", *issue.CopyID).First(&bookCopy).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", copyStatus).Error; err != nil {
+				return err
+			}
+
+			// A copy borrowed from another library is written off in the
+			// lender's inventory and its inter-library loan closed
+			if bookCopy.LibID != issue.LibID {
+				if err := closeDeclaredInterLibraryLoan(tx, &issue, disposition, now.Format(time.RFC3339)); err != nil {
+					return err
+				}
+				if err := syncCopyCounts(tx, bookCopy.BookID, bookCopy.LibID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := cancelLoanRequests(tx, &issue, adminID, "loan declared "+disposition); err != nil {
+			return err
+		}
+
+		if feeCents > 0 {
+			entry := model.FineLedgerEntry{
+				EntryID:     util.RandomUUID(),
+				ReaderID:    issue.ReaderID,
+				LibID:       issue.LibID,
+				IssueID:     &issue.IssueID,
+				EntryType:   fineType,
+				AmountCents: feeCents,
+				Note:        note,
+				RecordedBy:  &adminID,
+				EntryDate:   now.Format(time.RFC3339),
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+		}
+
+		return syncCopyCounts(tx, issue.BookID, issue.LibID)
+	})
+}
+
+// ReverseLoanDeclaration undoes a lost or damaged declaration once the book
+// turns up or proves fine. The loan is closed as a normal return, the copy is
+// made available again, or sent back to the library it was borrowed from, and
+// the declaration fee is credited back.
+func (admin *AdminRepository) ReverseLoanDeclaration(ctx context.Context, issueID string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var issue model.IssueRegistry
+		result := tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("issue_id = ?", issueID).
+			Where("lib_id = ?", adminUser.LibID).
+			Where("issue_status IN ?", []string{util.IssueStatusLost, util.IssueStatusDamaged}).
+			Limit(1).
+			Find(&issue)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no lost or damaged loan found in library")
+		}
+
+		now := time.Now().Format(time.RFC3339)
+		charged, err := declarationCharges(tx, &issue)
+		if err != nil {
+			return err
+		}
+		if charged > 0 {
+			entry := model.FineLedgerEntry{
+				EntryID:     util.RandomUUID(),
+				ReaderID:    issue.ReaderID,
+				LibID:       issue.LibID,
+				IssueID:     &issue.IssueID,
+				EntryType:   util.FineTypeWaiver,
+				AmountCents: -charged,
+				Note:        issue.IssueStatus + " declaration reversed",
+				RecordedBy:  &adminID,
+				EntryDate:   now,
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&model.IssueRegistry{}).Where("issue_id = ?", issue.IssueID).Updates(map[string]interface{}{
+			"issue_status":       util.IssueStatusClosed,
+			"return_date":        now,
+			"return_approver_id": adminID,
+		}).Error; err != nil {
+			return err
+		}
+		if issue.CopyID != nil {
+			var bookCopy model.BookCopy
+			if err := tx.Where("barcode = ?", *issue.CopyID).First(&bookCopy).Error; err != nil {
+				return err
+			}
+
+			// A copy borrowed from another library goes back to its lender
+			if bookCopy.LibID != issue.LibID {
+				if err := reopenDeclaredInterLibraryLoan(tx, &issue); err != nil {
+					return err
+				}
+				if err := sendCopyHome(tx, &issue, &bookCopy, now); err != nil {
+					return err
+				}
+				if err := syncCopyCounts(tx, bookCopy.BookID, bookCopy.LibID); err != nil {
+					return err
+				}
+			} else if err := tx.Model(&model.BookCopy{}).
+				Where("barcode = ?", bookCopy.Barcode).
+				Where("status IN ?", []string{util.CopyStatusLost, util.CopyStatusDamaged}).
+				Update("status", util.CopyStatusAvailable).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := promoteHolds(tx, issue.BookID, issue.LibID, admin.holdPickupWindow); err != nil {
+			return err
+		}
+		return syncCopyCounts(tx, issue.BookID, issue.LibID)
+	})
+}
+
+// DeskCheckout issues a book to a reader at the circulation desk without a
+// prior request. The reader is looked up by ID or email and goes through the
+// same checks as a raised request. A pending request of the reader for the
+// book is approved along with the loan.
+func (admin *AdminRepository) DeskCheckout(ctx context.Context, readerIdentifier string, isbn string, barcode string, adminID string, issue *model.IssueRegistry) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+		if adminUser.LibID == nil {
+			return errors.New("admin is not assigned to a library")
+		}
+
+		var reader model.Users
+		if err := deskReader(tx, *adminUser.LibID, readerIdentifier, &reader); err != nil {
+			return err
+		}
+
+		var openIssues int64
+		if err := tx.Model(&model.IssueRegistry{}).Where("reader_id = ?", reader.ID).Where("book_id = ?", isbn).Where("lib_id = ?", adminUser.LibID).Where("issue_status = ?", util.IssueStatusOpen).Count(&openIssues).Error; err != nil {
+			return err
+		}
+		if openIssues > 0 {
+			return errors.New("book already issued to reader")
+		}
+
+		var pendingRequest model.RequestEvents
+		result := tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("reader_id = ?", reader.ID).
+			Where("book_id = ?", isbn).
+			Where("lib_id = ?", adminUser.LibID).
+			Where("request_type = ?", util.IssueRequestType).
+			Where("status = ?", util.RequestStatusPending).
+			Limit(1).
+			Find(&pendingRequest)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var policy model.LibraryPolicy
+		if err := readerPolicy(tx, *adminUser.LibID, reader.ID, &policy); err != nil {
+			return err
+		}
+		if err := checkBorrower(tx, &reader, &policy, pendingRequest.ReqID); err != nil {
+			return err
+		}
+
+		if err := issueCopy(tx, reader.ID, isbn, *adminUser.LibID, barcode, adminID, adminUser.BranchID, &policy, issue); err != nil {
+			return err
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := transitionRequest(tx, &pendingRequest, util.RequestStatusApproved, &adminID, "issued at the desk", map[string]interface{}{
+			"approval_date": issue.IssueDate,
+			"approver_id":   adminID,
+		}); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventApproved, &pendingRequest)
+	})
+}
+
+// DeskCheckin returns the loaned copy with the given barcode at the
+// circulation desk. A pending return request for the loan is approved and
+// pending renewals are cancelled.
+func (admin *AdminRepository) DeskCheckin(ctx context.Context, barcode string, adminID string, issue *model.IssueRegistry) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		result := tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("copy_id = ?", barcode).
+			Where("lib_id = ?", adminUser.LibID).
+			Where("issue_status = ?", util.IssueStatusOpen).
+			Limit(1).
+			Find(issue)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("copy with supplied barcode is not on loan")
+		}
+
+		returnTime := time.Now()
+		var returnRequest model.RequestEvents
+		result = tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("reader_id = ?", issue.ReaderID).
+			Where("book_id = ?", issue.BookID).
+			Where("lib_id = ?", issue.LibID).
+			Where("request_type = ?", util.ReturnRequestType).
+			Where("status = ?", util.RequestStatusPending).
+			Limit(1).
+			Find(&returnRequest)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			if err := transitionRequest(tx, &returnRequest, util.RequestStatusApproved, &adminID, "checked in at the desk", map[string]interface{}{
+				"approval_date": returnTime.Format(time.RFC3339),
+				"approver_id":   adminID,
+			}); err != nil {
+				return err
+			}
+			if err := publishRequestEvent(tx, util.RequestEventApproved, &returnRequest); err != nil {
+				return err
+			}
+		}
+
+		if err := cancelLoanRequests(tx, issue, adminID, "book checked in at the desk"); err != nil {
+			return err
+		}
+		return returnIssue(tx, issue, adminID, adminUser.BranchID, returnTime, admin.holdPickupWindow)
+	})
+}
+
+// SearchReaders finds readers of the admin's library whose card number
+// matches the query exactly or whose name, email or phone contain it
+func (admin *AdminRepository) SearchReaders(ctx context.Context, query string, readers *[]model.Users, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		pattern := util.ContainsPattern(query)
+		return tx.Where("lib_id = ?", adminUser.LibID).
+			Where("role = ?", util.ReaderRole).
+			Where(`card_number = ? OR name ILIKE ? ESCAPE '\' OR email ILIKE ? ESCAPE '\' OR contact_number LIKE ? ESCAPE '\'`, query, pattern, pattern, pattern).
+			Order("name").
+			Limit(util.DefaultPageSize).
+			Find(readers).Error
+	})
+}
+
+// ReissueCard replaces the card number of a reader in the admin's library,
+// so a lost card no longer identifies them
+func (admin *AdminRepository) ReissueCard(ctx context.Context, readerID string, cardNumber *string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+		if err := libraryReader(tx, readerID, *adminUser.LibID); err != nil {
+			return err
+		}
+
+		newNumber, err := NewCardNumber(tx)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Users{}).Where("id = ?", readerID).Update("card_number", newNumber).Error; err != nil {
+			return err
+		}
+
+		*cardNumber = newNumber
+		return nil
+	})
+}
+
+// ListInterLibraryLoans lists the inter-library loans the admin's library lends
+// or borrows, optionally only those with the given status
+func (admin *AdminRepository) ListInterLibraryLoans(ctx context.Context, status string, loans *[]model.InterLibraryLoan, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		query := tx.Where("lender_lib_id = ? OR borrower_lib_id = ?", adminUser.LibID, adminUser.LibID)
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query.Order("request_date DESC").Find(loans).Error
+	})
+}
+
+// ShipInterLibraryLoan sends a copy of the lending library to the borrowing
+// library. The copy stays in the lender's stock while in transit.
+func (admin *AdminRepository) ShipInterLibraryLoan(ctx context.Context, illID string, barcode string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var loan model.InterLibraryLoan
+		if err := lockInterLibraryLoan(tx, illID, "lender_lib_id", *adminUser.LibID, util.InterLibraryStatusRequested, &loan); err != nil {
+			return err
+		}
+
+		var bookCopy model.BookCopy
+		if err := lockAvailableCopy(tx, loan.BookID, loan.LenderLibID, barcode, adminUser.BranchID, &bookCopy); err != nil {
+			return err
+		}
+		if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", util.CopyStatusInTransit).Error; err != nil {
+			return err
+		}
+		if err := syncCopyCounts(tx, loan.BookID, loan.LenderLibID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", illID).Updates(map[string]interface{}{
+			"status":       util.InterLibraryStatusShipped,
+			"copy_id":      bookCopy.Barcode,
+			"shipped_date": time.Now().Format(time.RFC3339),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := stockInterLibraryTitle(tx, loan.BookID, loan.LenderLibID, loan.BorrowerLibID); err != nil {
+			return err
+		}
+
+		var reader model.Users
+		if err := tx.Where("id = ?", loan.ReaderID).First(&reader).Error; err != nil {
+			return err
+		}
+		lenderName, err := libraryName(tx, loan.LenderLibID)
+		if err != nil {
+			return err
+		}
+		return notifyLibraryAdmins(tx, loan.BorrowerLibID, util.NotificationInterLibraryShipped, loan.BookID, &loan.ILLID, map[string]string{
+			"library_name": lenderName,
+			"reader_name":  reader.Name,
+		})
+	})
+}
+
+func (admin *AdminRepository) RejectInterLibraryLoan(ctx context.Context, illID string, reason string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var loan model.InterLibraryLoan
+		if err := lockInterLibraryLoan(tx, illID, "lender_lib_id", *adminUser.LibID, util.InterLibraryStatusRequested, &loan); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":      util.InterLibraryStatusRejected,
+			"closed_date": time.Now().Format(time.RFC3339),
+		}
+		if reason != "" {
+			updates["status_reason"] = reason
+		}
+		if err := tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", illID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		lenderName, err := libraryName(tx, loan.LenderLibID)
+		if err != nil {
+			return err
+		}
+		return notifyReader(tx, loan.ReaderID, util.NotificationInterLibraryRejected, loan.BookID, loan.LenderLibID, &loan.ILLID, map[string]string{
+			"library_name": lenderName,
+		})
+	})
+}
+
+// ReceiveInterLibraryLoan records the arrival of a shipped copy at the
+// borrowing library and tells the reader it is ready for pickup
+func (admin *AdminRepository) ReceiveInterLibraryLoan(ctx context.Context, illID string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var loan model.InterLibraryLoan
+		if err := lockInterLibraryLoan(tx, illID, "borrower_lib_id", *adminUser.LibID, util.InterLibraryStatusShipped, &loan); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", illID).Updates(map[string]interface{}{
+			"status":        util.InterLibraryStatusReceived,
+			"received_date": time.Now().Format(time.RFC3339),
+		}).Error; err != nil {
+			return err
+		}
+
+		lenderName, err := libraryName(tx, loan.LenderLibID)
+		if err != nil {
+			return err
+		}
+		return notifyReader(tx, loan.ReaderID, util.NotificationInterLibraryReady, loan.BookID, loan.BorrowerLibID, &loan.ILLID, map[string]string{
+			"library_name": lenderName,
+		})
+	})
+}
+
+// IssueInterLibraryLoan lends a received copy to the reader who requested it
+// as a loan of the borrowing library, under that library's policy
+func (admin *AdminRepository) IssueInterLibraryLoan(ctx context.Context, illID string, adminID string, issue *model.IssueRegistry) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var loan model.InterLibraryLoan
+		if err := lockInterLibraryLoan(tx, illID, "borrower_lib_id", *adminUser.LibID, util.InterLibraryStatusReceived, &loan); err != nil {
+			return err
+		}
+
+		var reader model.Users
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", loan.ReaderID).First(&reader).Error; err != nil {
+			return err
+		}
+
+		var policy model.LibraryPolicy
+		if err := readerPolicy(tx, loan.BorrowerLibID, loan.ReaderID, &policy); err != nil {
+			return err
+		}
+		if err := checkBorrower(tx, &reader, &policy, loan.ILLID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.BookCopy{}).
+			Where("barcode = ?", *loan.CopyID).
+			Where("lib_id = ?", loan.LenderLibID).
+			Where("status = ?", util.CopyStatusInTransit).
+			Update("status", util.CopyStatusIssued)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("copy on the inter-library loan is no longer in transit")
+		}
+
+		issueDate := time.Now()
+		*issue = model.IssueRegistry{
+			IssueID:            util.RandomUUID(),
+			BookID:             loan.BookID,
+			LibID:              loan.BorrowerLibID,
+			BranchID:           adminUser.BranchID,
+			CopyID:             loan.CopyID,
+			ReaderID:           loan.ReaderID,
+			IssueApproverID:    adminID,
+			IssueStatus:        util.IssueStatusOpen,
+			IssueDate:          issueDate.Format(time.RFC3339),
+			ExpectedReturnDate: issueDate.Add(loanPeriod(&policy)).Format(time.RFC3339),
+		}
+		if err := tx.Create(issue).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", illID).Updates(map[string]interface{}{
+			"status":   util.InterLibraryStatusIssued,
+			"issue_id": issue.IssueID,
+		}).Error
+	})
+}
+
+// ReceiveInterLibraryReturn puts a copy back into the lending library's
+// circulation once it arrives home, closing the inter-library loan
+func (admin *AdminRepository) ReceiveInterLibraryReturn(ctx context.Context, illID string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var loan model.InterLibraryLoan
+		if err := lockInterLibraryLoan(tx, illID, "lender_lib_id", *adminUser.LibID, util.InterLibraryStatusReturning, &loan); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", *loan.CopyID).Update("status", util.CopyStatusAvailable).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", illID).Updates(map[string]interface{}{
+			"status":      util.InterLibraryStatusReturned,
+			"closed_date": time.Now().Format(time.RFC3339),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := promoteHolds(tx, loan.BookID, loan.LenderLibID, admin.holdPickupWindow); err != nil {
+			return err
+		}
+		return syncCopyCounts(tx, loan.BookID, loan.LenderLibID)
+	})
+}
+
+// ListBranches lists the branches of the admin's library
+func (admin *AdminRepository) ListBranches(ctx context.Context, branches *[]model.Branch, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("lib_id = ?", adminUser.LibID).Order("name").Find(branches).Error
+	})
+}
+
+// TransferCopy moves a copy of the admin's library to another home branch.
+// An available copy is put in transit until the new branch receives it.
+func (admin *AdminRepository) TransferCopy(ctx context.Context, barcode string, branchID string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var bookCopy model.BookCopy
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("barcode = ?", barcode).Where("lib_id = ?", adminUser.LibID).First(&bookCopy).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("copy with supplied barcode not found in library")
+			}
+			return err
+		}
+		if bookCopy.BranchID != nil && *bookCopy.BranchID == branchID {
+			return errors.New("copy already belongs to branch")
+		}
+		switch bookCopy.Status {
+		case util.CopyStatusOnHold:
+			return errors.New("copy is held for a reader")
+		case util.CopyStatusLost, util.CopyStatusWithdrawn:
+			return errors.New("copy is no longer in circulation")
+		}
+
+		if err := libraryBranch(tx, *adminUser.LibID, branchID, &model.Branch{}); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"branch_id": branchID,
+		}
+		if bookCopy.Status == util.CopyStatusAvailable {
+			updates["status"] = util.CopyStatusInTransit
+		}
+		if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Updates(updates).Error; err != nil {
+			return err
+		}
+		return syncCopyCounts(tx, bookCopy.BookID, bookCopy.LibID)
+	})
+}
+
+// ReceiveBranchTransfer puts a copy in transit between branches of the
+// admin's library back into circulation once it reaches its home branch
+func (admin *AdminRepository) ReceiveBranchTransfer(ctx context.Context, barcode string, adminID string) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var adminUser model.Users
+		if err := tx.Where("id = ?", adminID).First(&adminUser).Error; err != nil {
+			return err
+		}
+
+		var bookCopy model.BookCopy
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("barcode = ?", barcode).Where("lib_id = ?", adminUser.LibID).First(&bookCopy).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("copy with supplied barcode not found in library")
+			}
+			return err
+		}
+		if bookCopy.Status != util.CopyStatusInTransit {
+			return errors.New("copy with supplied barcode is not in transit")
+		}
+		if adminUser.BranchID != nil && bookCopy.BranchID != nil && *adminUser.BranchID != *bookCopy.BranchID {
+			return errors.New("copy belongs to another branch")
+		}
+
+		var interLibraryLoans int64
+		if err := tx.Model(&model.InterLibraryLoan{}).
+			Where("copy_id = ?", bookCopy.Barcode).
+			Where("status IN ?", []string{util.InterLibraryStatusShipped, util.InterLibraryStatusReturning}).
+			Count(&interLibraryLoans).Error; err != nil {
+			return err
+		}
+		if interLibraryLoans > 0 {
+			return errors.New("copy is in transit on an inter-library loan")
+		}
+
+		if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", util.CopyStatusAvailable).Error; err != nil {
+			return err
+		}
+		if err := promoteHolds(tx, bookCopy.BookID, bookCopy.LibID, admin.holdPickupWindow); err != nil {
+			return err
+		}
+		return syncCopyCounts(tx, bookCopy.BookID, bookCopy.LibID)
+	})
+}
+
+// ExpireHolds expires ready holds whose pickup window has passed and hands
+// their copies to the next readers in the queue
+func (admin *AdminRepository) ExpireHolds(ctx context.Context) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var readyHolds []model.Hold
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("status = ?", util.HoldStatusReady).Order("queue_number").Find(&readyHolds).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range readyHolds {
+			if readyHolds[i].PickupDeadline == nil {
+				continue
+			}
+			pickupDeadline, err := time.Parse(time.RFC3339, *readyHolds[i].PickupDeadline)
+			if err != nil {
+				return err
+			}
+			if now.Before(pickupDeadline) {
+				continue
+			}
+
+			if err := closeHold(tx, &readyHolds[i], util.HoldStatusExpired, admin.holdPickupWindow); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// bookCopyBackfillLock is the advisory lock key that serialises the copy
+// backfill across replicas starting at the same time
+const bookCopyBackfillLock = 7_201_001
+
+// BackfillBookCopies creates copy records for books that were stocked before
+// copies were tracked individually, linking open issues to the issued copies.
+// Replicas take turns, so a book is never backfilled twice.
+func (admin *AdminRepository) BackfillBookCopies(ctx context.Context) error {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	return admin.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", bookCopyBackfillLock).Error; err != nil {
+			return err
+		}
+
+		var books []model.BookInventory
+		if err := tx.Where("NOT EXISTS (SELECT 1 FROM book_copies c WHERE c.book_id = book_inventories.isbn AND c.lib_id = book_inventories.lib_id)").Find(&books).Error; err != nil {
+			return err
+		}
+
+		for _, book := range books {
+			var openIssues []model.IssueRegistry
+			if err := tx.Where("book_id = ?", book.ISBN).Where("lib_id = ?", book.LibID).Where("issue_status = ?", util.IssueStatusOpen).Find(&openIssues).Error; err != nil {
+				return err
+			}
+
+			totalCopies := int(book.TotalCopies)
+			if totalCopies < len(openIssues) {
+				totalCopies = len(openIssues)
+			}
+
+			addedDate := time.Now().Format(time.RFC3339)
+			for i := 0; i < totalCopies; i++ {
+				bookCopy := model.BookCopy{
+					Barcode:   util.RandomBarcode(),
+					BookID:    book.ISBN,
+					LibID:     *book.LibID,
+					Status:    util.CopyStatusAvailable,
+					AddedDate: addedDate,
+				}
+				if i < len(openIssues) {
+					bookCopy.Status = util.CopyStatusIssued
+				}
+				if err := tx.Create(&bookCopy).Error; err != nil {
+					return err
+				}
+
+				if i < len(openIssues) {
+					if err := tx.Model(&model.IssueRegistry{}).Where("issue_id = ?", openIssues[i].IssueID).Update("copy_id", bookCopy.Barcode).Error; err != nil {
+						return err
+					}
+				}
+			}
+
+			if err := syncCopyCounts(tx, book.ISBN, *book.LibID); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
@@ -7,6 +7,7 @@ import (
 	"library-management/backend/internal/database/transaction"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -38,7 +39,7 @@ func (s *AdminRepositoryTestSuite) SetupTest() {
 	assert.NoError(s.T(), err)
 
 	txManager := transaction.NewTxManager(s.db)
-	s.admin = NewAdminRepository(s.db, txManager)
+	s.admin = NewAdminRepository(s.db, txManager, 72*time.Hour)
 	s.ctx = context.Background()
 }
 
@@ -78,7 +79,12 @@ func (s *AdminRepositoryTestSuite) TestAddBook() {
 
 	s.mock.ExpectCommit()
 
-	err := s.admin.AddBook(s.ctx, &book, "admin@test.com")
+	bookCopy := model.BookCopy{
+		Barcode: "BARCODE0001",
+		Status:  "available",
+	}
+
+	err := s.admin.AddBook(s.ctx, &book, &bookCopy, "admin@test.com")
 	assert.NoError(s.T(), err)
 }
 
@@ -107,6 +113,8 @@ func (s *AdminRepositoryTestSuite) TestApproveIssueRequest() {
 	// Mock update request events
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "request_events"`)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "request_status_changes"`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Mock create issue registry
 	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "issue_registries"`)).
@@ -114,7 +122,7 @@ func (s *AdminRepositoryTestSuite) TestApproveIssueRequest() {
 
 	s.mock.ExpectCommit()
 
-	err := s.admin.ApproveIssueRequest(s.ctx, requestID, approverID)
+	err := s.admin.ApproveIssueRequest(s.ctx, requestID, approverID, "")
 	assert.NoError(s.T(), err)
 }
 
@@ -134,7 +142,7 @@ func (s *AdminRepositoryTestSuite) TestRejectIssueRequest() {
 
 	s.mock.ExpectCommit()
 
-	err := s.admin.RejectIssueRequest(s.ctx, requestID)
+	err := s.admin.RejectIssueRequest(s.ctx, requestID, "admin123", "")
 	assert.NoError(s.T(), err)
 }
 
@@ -153,3 +161,1232 @@ func (s *AdminRepositoryTestSuite) TestListIssueRequests() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), 1, len(requests))
 }
+
+func (s *AdminRepositoryTestSuite) TestApproveReturnRequest() {
+	requestID := "req456"
+	approverID := "admin123"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs(approverID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow(approverID, "admin", "lib123"))
+
+	// Mock existing return request query
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2 AND request_type = $3`)).
+		WithArgs(requestID, "lib123", "return", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow(requestID, "1234567890", "lib123", "reader123", "return", "pending"))
+
+	// Mock open issue query
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries"`)).
+		WithArgs("reader123", "1234567890", "lib123", "open", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "copy_id", "reader_id", "issue_status"}).
+			AddRow("issue123", "1234567890", "lib123", "BARCODE0001", "reader123", "open"))
+
+	// Mock update request events
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "request_events"`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "request_status_changes"`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock approver working at the copy's home branch
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","branch_id" FROM "users" WHERE id = $1`)).
+		WithArgs(approverID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "branch_id"}).
+			AddRow(approverID, "branch123"))
+
+	// Mock close issue registry
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_branch_id"=$3,"return_date"=$4 WHERE issue_id = $5`)).
+		WithArgs("closed", approverID, "branch123", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock returned copy owned by the library
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "branch_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "branch123", "issued"))
+
+	// Mock returned copy made available again
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("available", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock returned copy reserved for the next hold in the queue
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds" WHERE book_id = $1 AND lib_id = $2 AND status = $3 ORDER BY queue_number LIMIT $4`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id", "book_id", "lib_id", "reader_id", "status"}).
+			AddRow("hold123", "1234567890", "lib123", "reader456", "waiting"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE book_id = $1 AND lib_id = $2 AND status = $3 ORDER BY barcode LIMIT $4`)).
+		WithArgs("1234567890", "lib123", "available", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "available"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("on_hold", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "holds" SET "copy_id"=$1,"pickup_deadline"=$2,"ready_date"=$3,"status"=$4 WHERE hold_id = $5`)).
+		WithArgs("BARCODE0001", sqlmock.AnyArg(), sqlmock.AnyArg(), "ready", "hold123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock hold ready notification and email queued in the outbox
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories"`)).
+		WithArgs("1234567890", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "lib_id", "title"}).
+			AddRow("1234567890", "lib123", "Go Programming"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "notifications"`)).
+		WithArgs(sqlmock.AnyArg(), "reader456", "hold_ready", sqlmock.AnyArg(), "1234567890", nil, false, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WithArgs("reader456", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow("reader456", "Next Reader", "next@example.com"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "outbox_emails"`)).
+		WithArgs(sqlmock.AnyArg(), "reader456", "next@example.com", "hold_ready", sqlmock.AnyArg(), "pending", 0, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+
+	// Mock inventory counters derived from copies
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(0, 2, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock request event published to dashboards
+	s.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs("request_events", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.ApproveReturnRequest(s.ctx, requestID, approverID)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestRejectReturnRequest_AlreadyApproved() {
+	requestID := "req456"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2 AND request_type = $3`)).
+		WithArgs(requestID, "lib123", "return", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "request_type", "approver_id"}).
+			AddRow(requestID, "return", "admin123"))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.RejectReturnRequest(s.ctx, requestID, "admin123", "")
+	assert.EqualError(s.T(), err, "return request already approved")
+}
+
+func (s *AdminRepositoryTestSuite) TestApproveReturnRequest_OtherLibrary() {
+	requestID := "req456"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2 AND request_type = $3`)).
+		WithArgs(requestID, "lib123", "return", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ApproveReturnRequest(s.ctx, requestID, "admin123")
+	assert.EqualError(s.T(), err, "invalid Return Request ID")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestApproveIssueRequest_ScannedCopyNotAvailable() {
+	requestID := "req123"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2`)).
+		WithArgs(requestID, "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow(requestID, "1234567890", "lib123", "reader123", "issue", "pending"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans"}).
+			AddRow("lib123", 14, 5))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id"`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "issue_registries"`)).
+		WithArgs("reader123", "lib123", "open").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "request_events"`)).
+		WithArgs("reader123", "lib123", "issue", "pending", requestID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "inter_library_loans"`)).
+		WithArgs("reader123", "lib123", "requested", "shipped", "received", requestID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","branch_id" FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "branch_id"}).
+			AddRow("admin123", "branch123"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories"`)).
+		WithArgs("1234567890", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "available_copies"}).
+			AddRow("1234567890", 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("reader123", "1234567890", "lib123", "ready", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE book_id = $1 AND lib_id = $2 AND barcode = $3`)).
+		WithArgs("1234567890", "lib123", "BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "damaged"))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ApproveIssueRequest(s.ctx, requestID, "admin123", "BARCODE0001")
+	assert.EqualError(s.T(), err, "copy with supplied barcode is not available")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestApproveIssueRequest_LoanLimitReached() {
+	requestID := "req123"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2`)).
+		WithArgs(requestID, "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow(requestID, "1234567890", "lib123", "reader123", "issue", "pending"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans"}).
+			AddRow("lib123", 14, 3))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id"`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "issue_registries"`)).
+		WithArgs("reader123", "lib123", "open").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "request_events" WHERE reader_id = $1 AND lib_id = $2 AND request_type = $3 AND status = $4 AND req_id <> $5`)).
+		WithArgs("reader123", "lib123", "issue", "pending", requestID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "inter_library_loans" WHERE reader_id = $1 AND borrower_lib_id = $2 AND status IN ($3,$4,$5) AND ill_id <> $6`)).
+		WithArgs("reader123", "lib123", "requested", "shipped", "received", requestID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ApproveIssueRequest(s.ctx, requestID, "admin123", "")
+
+	var limitErr *LoanLimitError
+	assert.ErrorAs(s.T(), err, &limitErr)
+	assert.Equal(s.T(), uint(3), limitErr.Limit)
+	assert.Equal(s.T(), int64(2), limitErr.OpenLoans)
+	assert.Equal(s.T(), int64(1), limitErr.PendingRequests)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestResetReaderPassword() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE email = $1 AND role = $2 AND lib_id = $3`)).
+		WithArgs("reader@example.com", "reader", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "lib_id"}).
+			AddRow("reader123", "reader@example.com", "reader", "lib123"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "hashed_password"=$1 WHERE id = $2`)).
+		WithArgs("new-hash", "reader123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock every session of the reader signed out
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked"=$1 WHERE user_id = $2`)).
+		WithArgs(true, "reader123").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.ResetReaderPassword(s.ctx, "reader@example.com", "new-hash", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestBackfillBookCopies_AlreadyBackfilled() {
+	s.mock.ExpectBegin()
+
+	// Mock the lock held until another replica's backfill commits
+	s.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WithArgs(7201001).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories" WHERE NOT EXISTS (SELECT 1 FROM book_copies c WHERE c.book_id = book_inventories.isbn AND c.lib_id = book_inventories.lib_id)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "lib_id"}))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.BackfillBookCopies(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestExpireHolds() {
+	expiredDeadline := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	activeDeadline := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds" WHERE status = $1 ORDER BY queue_number`)).
+		WithArgs("ready").
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id", "book_id", "lib_id", "reader_id", "copy_id", "status", "pickup_deadline"}).
+			AddRow("hold123", "1234567890", "lib123", "reader123", "BARCODE0001", "ready", expiredDeadline).
+			AddRow("hold456", "1234567890", "lib123", "reader456", "BARCODE0002", "ready", activeDeadline))
+
+	// Mock expired hold closed and its copy put back on the shelf
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "holds" SET "status"=$1 WHERE hold_id = $2`)).
+		WithArgs("expired", "hold123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2 AND status = $3`)).
+		WithArgs("available", "BARCODE0001", "on_hold").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories"`)).
+		WithArgs(1, 2, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.ExpireHolds(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestRejectRenewalRequest_OtherLibrary() {
+	requestID := "req789"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2 AND request_type = $3`)).
+		WithArgs(requestID, "lib123", "renew", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.RejectRenewalRequest(s.ctx, requestID, "admin123", "")
+	assert.EqualError(s.T(), err, "invalid Renewal Request ID")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestApproveRenewalRequest_WaitingHolds() {
+	requestID := "req789"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND lib_id = $2 AND request_type = $3`)).
+		WithArgs(requestID, "lib123", "renew", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow(requestID, "1234567890", "lib123", "reader123", "renew", "pending"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries"`)).
+		WithArgs("reader123", "1234567890", "lib123", "open", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "reader_id", "issue_status", "renewal_count"}).
+			AddRow("issue123", "1234567890", "lib123", "reader123", "open", 0))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_renewals"}).
+			AddRow("lib123", 14, 2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id"`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ApproveRenewalRequest(s.ctx, requestID, "admin123")
+	assert.EqualError(s.T(), err, "cannot renew a book other readers are waiting for")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestAccrueOverdueFines() {
+	dueDate := time.Now().Add(-60 * time.Hour).Format(time.RFC3339)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE issue_status = $1`)).
+		WithArgs("open").
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "reader_id", "issue_status", "expected_return_date"}).
+			AddRow("issue123", "1234567890", "lib123", "reader123", "open", dueDate))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "daily_fine_cents", "fine_cap_cents"}).
+			AddRow("lib123", 25, 60))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id"`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+
+	// Mock overdue fine already charged for the loan
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(amount_cents), 0) FROM "fine_ledger_entries" WHERE issue_id = $1 AND entry_type = $2`)).
+		WithArgs("issue123", "overdue").
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(25))
+
+	// Three days overdue at 25 cents is capped at 60, so only the difference is charged
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "fine_ledger_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "reader123", "lib123", "issue123", "overdue", int64(35), "", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.AccrueOverdueFines(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestGetReaderLoanHistory() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 AND lib_id = $2 AND role = $3 LIMIT $4`)).
+		WithArgs("reader123", "lib123", "reader", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("reader123", "reader", "lib123"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "issue_registries" WHERE reader_id = $1 AND lib_id = $2`)).
+		WithArgs("reader123", "lib123").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	// Second page of two loans per page
+	s.mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY i.issue_date DESC LIMIT $3 OFFSET $4`)).
+		WithArgs("reader123", "lib123", 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "book_title", "issue_status"}).
+			AddRow("issue1", "1234567890", "Go Programming", "closed"))
+
+	s.mock.ExpectCommit()
+
+	var loans []model.LoanDetails
+	var total int64
+	err := s.admin.GetReaderLoanHistory(s.ctx, "reader123", 2, 2, &loans, &total, "admin123")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(3), total)
+	assert.Len(s.T(), loans, 1)
+	assert.Equal(s.T(), "Go Programming", loans[0].BookTitle)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestGetReaderLoans_ReaderOutsideLibrary() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 AND lib_id = $2 AND role = $3 LIMIT $4`)).
+		WithArgs("reader999", "lib123", "reader", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	s.mock.ExpectRollback()
+
+	var loans []model.LoanDetails
+	err := s.admin.GetReaderLoans(s.ctx, "reader999", &loans, "admin123")
+	assert.EqualError(s.T(), err, "reader not found in library")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestAssignTier_TierNotInLibrary() {
+	tierID := "tier999"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 AND lib_id = $2 AND role = $3`)).
+		WithArgs("reader123", "lib123", "reader", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("reader123", "reader", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "membership_tiers" WHERE tier_id = $1 AND lib_id = $2`)).
+		WithArgs(tierID, "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.AssignTier(s.ctx, "reader123", &tierID, nil, "admin123")
+	assert.EqualError(s.T(), err, "tier not found in library")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestShipInterLibraryLoan_NotRequested() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inter_library_loans" WHERE ill_id = $1 AND lender_lib_id = $2`)).
+		WithArgs("ill123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ill_id", "book_id", "lender_lib_id", "borrower_lib_id", "reader_id", "status"}).
+			AddRow("ill123", "1234567890", "lib123", "lib456", "reader123", "cancelled"))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ShipInterLibraryLoan(s.ctx, "ill123", "", "admin123")
+	assert.EqualError(s.T(), err, "inter-library loan is cancelled")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestReceiveInterLibraryLoan_OtherLibrary() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inter_library_loans" WHERE ill_id = $1 AND borrower_lib_id = $2`)).
+		WithArgs("ill123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ill_id"}))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ReceiveInterLibraryLoan(s.ctx, "ill123", "admin123")
+	assert.EqualError(s.T(), err, "inter-library loan not found")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestDeclareLoan_Lost() {
+	dueDate := time.Now().Add(48 * time.Hour).Format(time.RFC3339)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE issue_id = $1 AND lib_id = $2 AND issue_status = $3`)).
+		WithArgs("issue123", "lib123", "open", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "copy_id", "reader_id", "issue_status", "expected_return_date"}).
+			AddRow("issue123", "1234567890", "lib123", "BARCODE0001", "reader123", "open", dueDate))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_date"=$3 WHERE issue_id = $4`)).
+		WithArgs("lost", "admin123", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "issued"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("lost", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock the pending renewal of the loan being cancelled
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE reader_id = $1 AND book_id = $2 AND lib_id = $3 AND request_type IN ($4,$5) AND status = $6`)).
+		WithArgs("reader123", "1234567890", "lib123", "return", "renew", "pending").
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow("req789", "1234567890", "lib123", "reader123", "renew", "pending"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "request_events" SET "closed_date"=$1,"status"=$2,"status_reason"=$3 WHERE req_id = $4`)).
+		WithArgs(sqlmock.AnyArg(), "cancelled", "loan declared lost", "req789").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "request_status_changes"`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs("request_events", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "fine_ledger_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "reader123", "lib123", "issue123", "lost", int64(2500), "replacement copy", "admin123", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(1, 1, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.DeclareLoan(s.ctx, "issue123", "lost", 2500, "replacement copy", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestReverseLoanDeclaration() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE issue_id = $1 AND lib_id = $2 AND issue_status IN ($3,$4)`)).
+		WithArgs("issue123", "lib123", "lost", "damaged", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "copy_id", "reader_id", "issue_status"}).
+			AddRow("issue123", "1234567890", "lib123", "BARCODE0001", "reader123", "lost"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(amount_cents), 0) FROM "fine_ledger_entries" WHERE issue_id = $1 AND entry_type = $2`)).
+		WithArgs("issue123", "lost").
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(2500))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "fine_ledger_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "reader123", "lib123", "issue123", "waiver", int64(-2500), "lost declaration reversed", "admin123", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_date"=$3 WHERE issue_id = $4`)).
+		WithArgs("closed", "admin123", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "lost"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2 AND status IN ($3,$4)`)).
+		WithArgs("available", "BARCODE0001", "lost", "damaged").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories"`)).
+		WithArgs(2, 2, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.ReverseLoanDeclaration(s.ctx, "issue123", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestDeclareLoan_InterLibraryCopy() {
+	dueDate := time.Now().Add(48 * time.Hour).Format(time.RFC3339)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE issue_id = $1 AND lib_id = $2 AND issue_status = $3`)).
+		WithArgs("issue123", "lib123", "open", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "copy_id", "reader_id", "issue_status", "expected_return_date"}).
+			AddRow("issue123", "1234567890", "lib123", "BARCODE0001", "reader123", "open", dueDate))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_date"=$3 WHERE issue_id = $4`)).
+		WithArgs("lost", "admin123", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock the copy borrowed from lib456 written off there
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib456", "issued"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("lost", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock the inter-library loan closed and the lender told
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inter_library_loans" WHERE issue_id = $1 AND status = $2`)).
+		WithArgs("issue123", "issued", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ill_id", "book_id", "lender_lib_id", "borrower_lib_id", "reader_id", "status"}).
+			AddRow("ill123", "1234567890", "lib456", "lib123", "reader123", "issued"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "inter_library_loans" SET "closed_date"=$1,"status"=$2 WHERE ill_id = $3`)).
+		WithArgs(sqlmock.AnyArg(), "lost", "ill123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "libraries" WHERE id = $1`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("lib123", "City Library"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories" WHERE isbn = $1 AND lib_id = $2`)).
+		WithArgs("1234567890", "lib456", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "lib_id", "title"}).
+			AddRow("1234567890", "lib456", "Go Programming"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE lib_id = $1 AND role = $2`)).
+		WithArgs("lib456", "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin456", "admin", "lib456"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "notifications"`)).
+		WithArgs(sqlmock.AnyArg(), "admin456", "inter_library_declared", "City Library declared your copy of Go Programming lost", "1234567890", "ill123", false, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock the lender's counts synced
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(1, 1, "1234567890", "lib456").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE reader_id = $1 AND book_id = $2 AND lib_id = $3 AND request_type IN ($4,$5) AND status = $6`)).
+		WithArgs("reader123", "1234567890", "lib123", "return", "renew", "pending").
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+
+	// Mock the borrower's catalogue entry synced
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(0, 0, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.DeclareLoan(s.ctx, "issue123", "lost", 0, "", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestReverseLoanDeclaration_InterLibraryCopy() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE issue_id = $1 AND lib_id = $2 AND issue_status IN ($3,$4)`)).
+		WithArgs("issue123", "lib123", "lost", "damaged", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "copy_id", "reader_id", "issue_status"}).
+			AddRow("issue123", "1234567890", "lib123", "BARCODE0001", "reader123", "lost"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(amount_cents), 0) FROM "fine_ledger_entries" WHERE issue_id = $1 AND entry_type = $2`)).
+		WithArgs("issue123", "lost").
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_date"=$3 WHERE issue_id = $4`)).
+		WithArgs("closed", "admin123", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib456", "lost"))
+
+	// Mock the inter-library loan reopened and the copy sent home
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "inter_library_loans" SET "closed_date"=$1,"status"=$2 WHERE issue_id = $3 AND status IN ($4,$5)`)).
+		WithArgs(nil, "issued", "issue123", "lost", "damaged").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("in_transit", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inter_library_loans" WHERE issue_id = $1 AND status = $2`)).
+		WithArgs("issue123", "issued", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ill_id", "book_id", "lender_lib_id", "borrower_lib_id", "reader_id", "status"}).
+			AddRow("ill123", "1234567890", "lib456", "lib123", "reader123", "issued"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "inter_library_loans" SET "return_shipped_date"=$1,"status"=$2 WHERE ill_id = $3`)).
+		WithArgs(sqlmock.AnyArg(), "returning", "ill123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "libraries" WHERE id = $1`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("lib123", "City Library"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories" WHERE isbn = $1 AND lib_id = $2`)).
+		WithArgs("1234567890", "lib456", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "lib_id", "title"}).
+			AddRow("1234567890", "lib456", "Go Programming"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE lib_id = $1 AND role = $2`)).
+		WithArgs("lib456", "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin456", "admin", "lib456"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "notifications"`)).
+		WithArgs(sqlmock.AnyArg(), "admin456", "inter_library_returned", sqlmock.AnyArg(), "1234567890", "ill123", false, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock the lender's counts synced, the copy no longer lost
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(1, 2, "1234567890", "lib456").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories"`)).
+		WithArgs(0, 0, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.ReverseLoanDeclaration(s.ctx, "issue123", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestDeskCheckout_ReaderNotFound() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE (id = $1 OR email = $2 OR card_number = $3) AND lib_id = $4 AND role = $5 LIMIT $6`)).
+		WithArgs("someone@example.com", "someone@example.com", "someone@example.com", "lib123", "reader", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	s.mock.ExpectRollback()
+
+	var issue model.IssueRegistry
+	err := s.admin.DeskCheckout(s.ctx, "someone@example.com", "1234567890", "", "admin123", &issue)
+	assert.EqualError(s.T(), err, "reader not found in library")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestUpdateCopy_ShelfLocationOnly() {
+	shelfLocation := "B-12"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1 AND lib_id = $2`)).
+		WithArgs("BARCODE0001", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "condition", "shelf_location", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "good", "A-01", "available"))
+
+	// Only the supplied field is written, leaving the condition as it was
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "shelf_location"=$1 WHERE barcode = $2`)).
+		WithArgs(shelfLocation, "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories"`)).
+		WithArgs(1, 1, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.UpdateCopy(s.ctx, "BARCODE0001", nil, &shelfLocation, "", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestDeskCheckin() {
+	dueDate := time.Now().Add(48 * time.Hour).Format(time.RFC3339)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE copy_id = $1 AND lib_id = $2 AND issue_status = $3`)).
+		WithArgs("BARCODE0001", "lib123", "open", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "copy_id", "reader_id", "issue_status", "expected_return_date"}).
+			AddRow("issue123", "1234567890", "lib123", "BARCODE0001", "reader123", "open", dueDate))
+
+	// Mock no pending return or renewal requests for the loan
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE reader_id = $1 AND book_id = $2 AND lib_id = $3 AND request_type = $4 AND status = $5`)).
+		WithArgs("reader123", "1234567890", "lib123", "return", "pending", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE reader_id = $1 AND book_id = $2 AND lib_id = $3 AND request_type IN ($4,$5) AND status = $6`)).
+		WithArgs("reader123", "1234567890", "lib123", "return", "renew", "pending").
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_date"=$3 WHERE issue_id = $4`)).
+		WithArgs("closed", "admin123", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "issued"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("available", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories"`)).
+		WithArgs(1, 1, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	var issue model.IssueRegistry
+	err := s.admin.DeskCheckin(s.ctx, "BARCODE0001", "admin123", &issue)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "closed", issue.IssueStatus)
+	assert.NotNil(s.T(), issue.ReturnDate)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestDeskCheckin_OtherBranch() {
+	dueDate := time.Now().Add(48 * time.Hour).Format(time.RFC3339)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id", "branch_id"}).
+			AddRow("admin123", "admin", "lib123", "branch456"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "issue_registries" WHERE copy_id = $1 AND lib_id = $2 AND issue_status = $3`)).
+		WithArgs("BARCODE0001", "lib123", "open", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"issue_id", "book_id", "lib_id", "branch_id", "copy_id", "reader_id", "issue_status", "expected_return_date"}).
+			AddRow("issue123", "1234567890", "lib123", "branch123", "BARCODE0001", "reader123", "open", dueDate))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE reader_id = $1 AND book_id = $2 AND lib_id = $3 AND request_type = $4 AND status = $5`)).
+		WithArgs("reader123", "1234567890", "lib123", "return", "pending", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE reader_id = $1 AND book_id = $2 AND lib_id = $3 AND request_type IN ($4,$5) AND status = $6`)).
+		WithArgs("reader123", "1234567890", "lib123", "return", "renew", "pending").
+		WillReturnRows(sqlmock.NewRows([]string{"req_id"}))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "issue_registries" SET "issue_status"=$1,"return_approver_id"=$2,"return_branch_id"=$3,"return_date"=$4 WHERE issue_id = $5`)).
+		WithArgs("closed", "admin123", "branch456", sqlmock.AnyArg(), "issue123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock copy shelved at another branch sent back there
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1`)).
+		WithArgs("BARCODE0001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "branch_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "branch123", "issued"))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("in_transit", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories"`)).
+		WithArgs(0, 1, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	var issue model.IssueRegistry
+	err := s.admin.DeskCheckin(s.ctx, "BARCODE0001", "admin123", &issue)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "closed", issue.IssueStatus)
+	if assert.NotNil(s.T(), issue.ReturnBranchID) {
+		assert.Equal(s.T(), "branch456", *issue.ReturnBranchID)
+	}
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestIssueInterLibraryLoan_MembershipExpired() {
+	expiry := time.Now().Add(-24 * time.Hour)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inter_library_loans" WHERE ill_id = $1 AND borrower_lib_id = $2`)).
+		WithArgs("ill123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ill_id", "book_id", "lender_lib_id", "borrower_lib_id", "reader_id", "copy_id", "status"}).
+			AddRow("ill123", "1234567890", "lib456", "lib123", "reader123", "BARCODE0001", "received"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("reader123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id", "membership_expiry"}).
+			AddRow("reader123", "reader", "lib123", expiry.Format(time.RFC3339)))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans", "max_balance_cents"}).
+			AddRow("lib123", 14, 1, 500))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id"`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+
+	s.mock.ExpectRollback()
+
+	var issue model.IssueRegistry
+	err := s.admin.IssueInterLibraryLoan(s.ctx, "ill123", "admin123", &issue)
+	assert.EqualError(s.T(), err, "membership expired on "+expiry.Format("2006-01-02")+", renew it to borrow books")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestIssueInterLibraryLoan_CopyNotInTransit() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inter_library_loans" WHERE ill_id = $1 AND borrower_lib_id = $2`)).
+		WithArgs("ill123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ill_id", "book_id", "lender_lib_id", "borrower_lib_id", "reader_id", "copy_id", "status"}).
+			AddRow("ill123", "1234567890", "lib456", "lib123", "reader123", "BARCODE0001", "received"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("reader123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id", "membership_expiry"}).
+			AddRow("reader123", "reader", "lib123", nil))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans", "max_balance_cents"}).
+			AddRow("lib123", 14, 1, 500))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id"`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+
+	// Mock the loan itself left out of the reader's limit
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "issue_registries"`)).
+		WithArgs("reader123", "lib123", "open").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "request_events"`)).
+		WithArgs("reader123", "lib123", "issue", "pending", "ill123").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "inter_library_loans" WHERE reader_id = $1 AND borrower_lib_id = $2 AND status IN ($3,$4,$5) AND ill_id <> $6`)).
+		WithArgs("reader123", "lib123", "requested", "shipped", "received", "ill123").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(amount_cents), 0) FROM "fine_ledger_entries" WHERE reader_id = $1 AND lib_id = $2`)).
+		WithArgs("reader123", "lib123").
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2 AND lib_id = $3 AND status = $4`)).
+		WithArgs("issued", "BARCODE0001", "lib456", "in_transit").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectRollback()
+
+	var issue model.IssueRegistry
+	err := s.admin.IssueInterLibraryLoan(s.ctx, "ill123", "admin123", &issue)
+	assert.EqualError(s.T(), err, "copy on the inter-library loan is no longer in transit")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestTransferCopy() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1 AND lib_id = $2`)).
+		WithArgs("BARCODE0001", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "branch_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "branch123", "available"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "branches" WHERE branch_id = $1 AND lib_id = $2`)).
+		WithArgs("branch456", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"branch_id", "lib_id", "name"}).
+			AddRow("branch456", "lib123", "East"))
+
+	// Mock the available copy sent on to its new branch
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "branch_id"=$1,"status"=$2 WHERE barcode = $3`)).
+		WithArgs("branch456", "in_transit", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(1, 2, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.TransferCopy(s.ctx, "BARCODE0001", "branch456", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestReceiveBranchTransfer() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id", "branch_id"}).
+			AddRow("admin123", "admin", "lib123", "branch456"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1 AND lib_id = $2`)).
+		WithArgs("BARCODE0001", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "branch_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "branch456", "in_transit"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "inter_library_loans" WHERE copy_id = $1 AND status IN ($2,$3)`)).
+		WithArgs("BARCODE0001", "shipped", "returning").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	// Mock the copy back in circulation at its home branch
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_copies" SET "status"=$1 WHERE barcode = $2`)).
+		WithArgs("available", "BARCODE0001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds"`)).
+		WithArgs("1234567890", "lib123", "waiting", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "book_copies"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "book_inventories" SET "available_copies"=$1,"total_copies"=$2 WHERE isbn = $3 AND lib_id = $4`)).
+		WithArgs(2, 2, "1234567890", "lib123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.admin.ReceiveBranchTransfer(s.ctx, "BARCODE0001", "admin123")
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestTransferCopy_Lost() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1 AND lib_id = $2`)).
+		WithArgs("BARCODE0001", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "branch_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "branch123", "lost"))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.TransferCopy(s.ctx, "BARCODE0001", "branch456", "admin123")
+	assert.EqualError(s.T(), err, "copy is no longer in circulation")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestReceiveBranchTransfer_OtherBranch() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id", "branch_id"}).
+			AddRow("admin123", "admin", "lib123", "branch456"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_copies" WHERE barcode = $1 AND lib_id = $2`)).
+		WithArgs("BARCODE0001", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"barcode", "book_id", "lib_id", "branch_id", "status"}).
+			AddRow("BARCODE0001", "1234567890", "lib123", "branch123", "in_transit"))
+
+	s.mock.ExpectRollback()
+
+	err := s.admin.ReceiveBranchTransfer(s.ctx, "BARCODE0001", "admin123")
+	assert.EqualError(s.T(), err, "copy belongs to another branch")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestGetReaderFines_ReaderNotInLibrary() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 AND lib_id = $2 AND role = $3`)).
+		WithArgs("reader999", "lib123", "reader", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	s.mock.ExpectRollback()
+
+	var entries []model.FineLedgerEntry
+	var balance int64
+	err := s.admin.GetReaderFines(s.ctx, "reader999", &entries, &balance, "admin123")
+	assert.EqualError(s.T(), err, "reader not found in library")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestSearchReaders() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE lib_id = $1 AND role = $2 AND (card_number = $3 OR name ILIKE $4 ESCAPE '\' OR email ILIKE $5 ESCAPE '\' OR contact_number LIKE $6 ESCAPE '\') ORDER BY name LIMIT $7`)).
+		WithArgs("lib123", "reader", "ali_", "%ali\\_%", "%ali\\_%", "%ali\\_%", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "role", "lib_id", "card_number"}).
+			AddRow("reader123", "Alice", "alice@example.com", "reader", "lib123", "1234-5678-9012"))
+
+	s.mock.ExpectCommit()
+
+	var readers []model.Users
+	err := s.admin.SearchReaders(s.ctx, "ali_", &readers, "admin123")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), readers, 1)
+	assert.Equal(s.T(), "1234-5678-9012", *readers[0].CardNumber)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AdminRepositoryTestSuite) TestReissueCard() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("admin123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("admin123", "admin", "lib123"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 AND lib_id = $2 AND role = $3`)).
+		WithArgs("reader123", "lib123", "reader", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id", "card_number"}).
+			AddRow("reader123", "reader", "lib123", "1234-5678-9012"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "users" WHERE card_number = $1`)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "card_number"=$1 WHERE id = $2`)).
+		WithArgs(sqlmock.AnyArg(), "reader123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	var cardNumber string
+	err := s.admin.ReissueCard(s.ctx, "reader123", &cardNumber, "admin123")
+	assert.NoError(s.T(), err)
+	assert.Regexp(s.T(), `^\d{4}-\d{4}-\d{4}$`, cardNumber)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
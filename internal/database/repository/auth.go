@@ -5,7 +5,9 @@ import (
 	"errors"
 	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/database/transaction"
+	"library-management/backend/internal/util"
 	"sync"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,6 +16,11 @@ type AuthRepositoryInterface interface {
 	Login(context.Context, string) (*model.Users, error)
 	UserDetails(context.Context, string) (*model.Users, error)
 	UserSignup(context.Context, model.Users) error
+	ChangePassword(context.Context, string, string, string) error
+	CreateRefreshToken(context.Context, *model.RefreshToken) error
+	RotateRefreshToken(context.Context, string, *model.RefreshToken, *model.Users) error
+	UpdateRefreshTokenAccessID(context.Context, string, string) error
+	RevokeRefreshToken(context.Context, string) error
 }
 
 type AuthRepository struct {
@@ -40,14 +47,17 @@ func (auth *AuthRepository) Login(ctx context.Context, email string, user *model
 	})
 }
 
-func (auth *AuthRepository) UserDetails(ctx context.Context, userID string, user *model.Users) error {
+func (auth *AuthRepository) UserDetails(ctx context.Context, userID string, user *model.Users, unreadNotifications *int64) error {
 	auth.mu.RLock()
 	defer auth.mu.RUnlock()
 
 	return auth.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
-		return tx.Set("gorm:query_option", "FOR SHARE").
+		if err := tx.Set("gorm:query_option", "FOR SHARE").
 			Where("ID = ?", userID).
-			First(&user).Error
+			First(&user).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Notification{}).Where("user_id = ?", userID).Where("read = ?", false).Count(unreadNotifications).Error
 	})
 }
 
@@ -75,6 +85,134 @@ func (auth *AuthRepository) UserSignup(ctx context.Context, user model.Users) er
 			return result.Error
 		}
 
+		if user.Role == util.ReaderRole {
+			cardNumber, err := NewCardNumber(tx)
+			if err != nil {
+				return err
+			}
+			user.CardNumber = &cardNumber
+		}
+
 		return tx.Model(&model.Users{}).Create(&user).Error
 	})
 }
+
+func (auth *AuthRepository) ChangePassword(ctx context.Context, userID string, oldPassword string, hashedPassword string) error {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	return auth.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var user model.Users
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", userID).First(&user)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if err := util.CheckPassword(oldPassword, user.HashedPassword); err != nil {
+			return errors.New("old password is incorrect")
+		}
+
+		if err := tx.Model(&model.Users{}).Where("id = ?", userID).Update("hashed_password", hashedPassword).Error; err != nil {
+			return err
+		}
+		return revokeUserRefreshTokens(tx, userID)
+	})
+}
+
+func (auth *AuthRepository) CreateRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) error {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	return auth.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&model.RefreshToken{}).Create(refreshToken).Error
+	})
+}
+
+// RotateRefreshToken revokes the refresh token matching hashedToken and stores
+// newToken in the same family. Presenting an already revoked token revokes the
+// whole family, since it means the token has been used by someone else.
+func (auth *AuthRepository) RotateRefreshToken(ctx context.Context, hashedToken string, newToken *model.RefreshToken, user *model.Users) error {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	reused := false
+	err := auth.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var existingToken model.RefreshToken
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("hashed_token = ?", hashedToken).First(&existingToken)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("refresh token is invalid")
+			}
+			return result.Error
+		}
+
+		if existingToken.Revoked {
+			reused = true
+			return tx.Model(&model.RefreshToken{}).Where("family_id = ?", existingToken.FamilyID).Update("revoked", true).Error
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, existingToken.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		if time.Now().After(expiresAt) {
+			return errors.New("refresh token has expired")
+		}
+
+		if err := tx.Where("id = ?", existingToken.UserID).First(user).Error; err != nil {
+			return err
+		}
+
+		newToken.FamilyID = existingToken.FamilyID
+		newToken.UserID = existingToken.UserID
+		if err := tx.Model(&model.RefreshToken{}).Where("id = ?", existingToken.ID).Updates(map[string]interface{}{
+			"revoked":     true,
+			"replaced_by": newToken.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.RefreshToken{}).Create(newToken).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if reused {
+		return errors.New("refresh token reuse detected, session revoked")
+	}
+	return nil
+}
+
+func (auth *AuthRepository) UpdateRefreshTokenAccessID(ctx context.Context, refreshTokenID string, accessTokenID string) error {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	return auth.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&model.RefreshToken{}).Where("id = ?", refreshTokenID).Update("access_token_id", accessTokenID).Error
+	})
+}
+
+func (auth *AuthRepository) RevokeRefreshToken(ctx context.Context, hashedToken string) error {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	return auth.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var existingToken model.RefreshToken
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("hashed_token = ?", hashedToken).First(&existingToken)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("refresh token is invalid")
+			}
+			return result.Error
+		}
+
+		return tx.Model(&model.RefreshToken{}).Where("family_id = ?", existingToken.FamilyID).Update("revoked", true).Error
+	})
+}
+
+// revokeUserRefreshTokens signs the user out of every session, so that a
+// stolen refresh token stops working once the password changes
+func revokeUserRefreshTokens(tx *gorm.DB, userID string) error {
+	return tx.Model(&model.RefreshToken{}).Where("user_id = ?", userID).Update("revoked", true).Error
+}
@@ -180,3 +180,87 @@ func TestAuthRepository_Login_TransactionError(t *testing.T) {
 	assert.Nil(t, user)
 	assert.Equal(t, sql.ErrConnDone, err)
 }
+
+func TestAuthRepository_ChangePassword_Success(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	txManager := transaction.NewTxManager(db)
+	repo := NewAuthRepository(db, txManager)
+	ctx := context.Background()
+
+	userID := util.RandomUUID()
+	oldPassword := util.RandomString(12)
+	oldHashedPassword, err := util.HashPassword(oldPassword)
+	assert.NoError(t, err)
+	newHashedPassword, err := util.HashPassword(util.RandomString(12))
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 ORDER BY "users"."id" LIMIT $2`)).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hashed_password"}).
+			AddRow(userID, oldHashedPassword))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "hashed_password"=$1 WHERE id = $2`)).
+		WithArgs(newHashedPassword, userID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked"=$1 WHERE user_id = $2`)).
+		WithArgs(true, userID).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	err = repo.ChangePassword(ctx, userID, oldPassword, newHashedPassword)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthRepository_ChangePassword_WrongOldPassword(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	txManager := transaction.NewTxManager(db)
+	repo := NewAuthRepository(db, txManager)
+	ctx := context.Background()
+
+	userID := util.RandomUUID()
+	oldHashedPassword, err := util.HashPassword(util.RandomString(12))
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 ORDER BY "users"."id" LIMIT $2`)).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hashed_password"}).
+			AddRow(userID, oldHashedPassword))
+	mock.ExpectRollback()
+
+	err = repo.ChangePassword(ctx, userID, util.RandomString(12), "new-hash")
+	assert.EqualError(t, err, "old password is incorrect")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthRepository_RotateRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	txManager := transaction.NewTxManager(db)
+	repo := NewAuthRepository(db, txManager)
+	ctx := context.Background()
+
+	hashedToken := "hashed-token"
+	familyID := util.RandomUUID()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "refresh_tokens" WHERE hashed_token = $1 ORDER BY "refresh_tokens"."id" LIMIT $2`)).
+		WithArgs(hashedToken, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "family_id", "hashed_token", "revoked"}).
+			AddRow(util.RandomUUID(), familyID, hashedToken, true))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked"=$1 WHERE family_id = $2`)).
+		WithArgs(true, familyID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	var user model.Users
+	err = repo.RotateRefreshToken(ctx, hashedToken, &model.RefreshToken{ID: util.RandomUUID()}, &user)
+	assert.EqualError(t, err, "refresh token reuse detected, session revoked")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
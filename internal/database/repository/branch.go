@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+
+	"gorm.io/gorm"
+)
+
+// libraryBranch loads the branch with the given ID if it belongs to the library
+func libraryBranch(tx *gorm.DB, libID string, branchID string, branch *model.Branch) error {
+	result := tx.Where("branch_id = ?", branchID).Where("lib_id = ?", libID).Limit(1).Find(branch)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("branch not found in library")
+	}
+	return nil
+}
+
+// staffBranch returns the branch the given admin works at, or nil for staff
+// who serve the whole library
+func staffBranch(tx *gorm.DB, userID string) (*string, error) {
+	var staff model.Users
+	if err := tx.Select("id", "branch_id").Where("id = ?", userID).First(&staff).Error; err != nil {
+		return nil, err
+	}
+	return staff.BranchID, nil
+}
+
+// returnedCopyStatus is the status of a copy checked back in at the given
+// branch: available at its home branch, otherwise in transit back to it.
+// Copies without a home branch, or checked in by staff serving the whole
+// library, become available where they are.
+func returnedCopyStatus(bookCopy *model.BookCopy, branchID *string) string {
+	if bookCopy.BranchID != nil && branchID != nil && *bookCopy.BranchID != *branchID {
+		return util.CopyStatusInTransit
+	}
+	return util.CopyStatusAvailable
+}
+
+// branchDetails loads the branches of the given library with their staff,
+// stock and circulation counts
+func branchDetails(tx *gorm.DB, libID string, branches *[]model.BranchDetails) error {
+	query := `SELECT br.*,
+							COALESCE(a.admins, 0) as admins,
+							COALESCE(c.total_copies, 0) as total_copies,
+							COALESCE(c.available_copies, 0) as available_copies,
+							COALESCE(c.issued_copies, 0) as issued_copies,
+							COALESCE(c.in_transit_copies, 0) as in_transit_copies,
+							COALESCE(i.open_loans, 0) as open_loans,
+							COALESCE(i.loans_issued, 0) as loans_issued,
+							COALESCE(rt.loans_returned, 0) as loans_returned
+						FROM branches br
+						LEFT JOIN (
+							SELECT branch_id, COUNT(*) as admins
+							FROM users
+							WHERE role = ?
+							GROUP BY branch_id
+						) a ON a.branch_id = br.branch_id
+						LEFT JOIN (
+							SELECT branch_id,
+								COUNT(*) FILTER (WHERE status NOT IN ?) as total_copies,
+								COUNT(*) FILTER (WHERE status = ?) as available_copies,
+								COUNT(*) FILTER (WHERE status = ?) as issued_copies,
+								COUNT(*) FILTER (WHERE status = ?) as in_transit_copies
+							FROM book_copies
+							GROUP BY branch_id
+						) c ON c.branch_id = br.branch_id
+						LEFT JOIN (
+							SELECT branch_id, COUNT(*) FILTER (WHERE issue_status = ?) as open_loans, COUNT(*) as loans_issued
+							FROM issue_registries
+							GROUP BY branch_id
+						) i ON i.branch_id = br.branch_id
+						LEFT JOIN (
+							SELECT return_branch_id, COUNT(*) as loans_returned
+							FROM issue_registries
+							GROUP BY return_branch_id
+						) rt ON rt.return_branch_id = br.branch_id
+						WHERE br.lib_id = ?
+						ORDER BY br.name`
+
+	return tx.Raw(query,
+		util.AdminRole,
+		[]string{util.CopyStatusLost, util.CopyStatusWithdrawn},
+		util.CopyStatusAvailable,
+		util.CopyStatusIssued,
+		util.CopyStatusInTransit,
+		util.IssueStatusOpen,
+		libID,
+	).Scan(branches).Error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+
+	"gorm.io/gorm"
+)
+
+const cardNumberAttempts = 5
+
+// NewCardNumber generates a library card number that no user holds yet,
+// giving up after a few collisions
+func NewCardNumber(tx *gorm.DB) (string, error) {
+	for i := 0; i < cardNumberAttempts; i++ {
+		cardNumber := util.RandomCardNumber()
+
+		var holders int64
+		if err := tx.Model(&model.Users{}).Where("card_number = ?", cardNumber).Count(&holders).Error; err != nil {
+			return "", err
+		}
+		if holders == 0 {
+			return cardNumber, nil
+		}
+	}
+	return "", errors.New("could not generate a unique card number")
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// issueCopy lends a copy of a book to a reader at the given branch and
+// records the loan. The copy held for the reader is used when one is ready,
+// otherwise the scanned or first available copy.
+func issueCopy(tx *gorm.DB, readerID string, isbn string, libID string, barcode string, approverID string, branchID *string, policy *model.LibraryPolicy, issue *model.IssueRegistry) error {
+	var bookInventory model.BookInventory
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", isbn).Where("lib_id = ?", libID).First(&bookInventory).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("book with supplied ISBN not found in library")
+		}
+		return err
+	}
+
+	var readyHold model.Hold
+	result := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("reader_id = ?", readerID).
+		Where("book_id = ?", isbn).
+		Where("lib_id = ?", libID).
+		Where("status = ?", util.HoldStatusReady).
+		Limit(1).
+		Find(&readyHold)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	var bookCopy model.BookCopy
+	if result.RowsAffected > 0 && readyHold.CopyID != nil {
+		if barcode != "" && barcode != *readyHold.CopyID {
+			return errors.New("reader's hold is for copy " + *readyHold.CopyID)
+		}
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("barcode = ?", *readyHold.CopyID).First(&bookCopy).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Hold{}).Where("hold_id = ?", readyHold.HoldID).Update("status", util.HoldStatusFulfilled).Error; err != nil {
+			return err
+		}
+	} else if err := lockAvailableCopy(tx, bookInventory.ISBN, libID, barcode, branchID, &bookCopy); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", util.CopyStatusIssued).Error; err != nil {
+		return err
+	}
+	if err := syncCopyCounts(tx, bookInventory.ISBN, libID); err != nil {
+		return err
+	}
+
+	issueDate := time.Now()
+	*issue = model.IssueRegistry{
+		IssueID:            util.RandomUUID(),
+		BookID:             isbn,
+		LibID:              libID,
+		BranchID:           branchID,
+		CopyID:             &bookCopy.Barcode,
+		ReaderID:           readerID,
+		IssueApproverID:    approverID,
+		IssueStatus:        util.IssueStatusOpen,
+		IssueDate:          issueDate.Format(time.RFC3339),
+		ExpectedReturnDate: issueDate.Add(loanPeriod(policy)).Format(time.RFC3339),
+		ReturnDate:         nil,
+		ReturnApproverID:   nil,
+	}
+	return tx.Model(&model.IssueRegistry{}).Create(issue).Error
+}
+
+// returnIssue closes a loan as returned at the given branch, settling its
+// overdue fine, and puts the copy back into circulation for the next reader
+// waiting for it. A copy owned by another library or shelved at another
+// branch is sent home instead.
+func returnIssue(tx *gorm.DB, issue *model.IssueRegistry, approverID string, branchID *string, returnTime time.Time, pickupWindow time.Duration) error {
+	if err := accrueOverdueOnReturn(tx, issue, returnTime); err != nil {
+		return err
+	}
+
+	returnDate := returnTime.Format(time.RFC3339)
+	updates := map[string]interface{}{
+		"return_date":        returnDate,
+		"return_approver_id": approverID,
+		"issue_status":       util.IssueStatusClosed,
+	}
+	if branchID != nil {
+		updates["return_branch_id"] = *branchID
+	}
+	if err := tx.Model(&model.IssueRegistry{}).Where("issue_id = ?", issue.IssueID).Updates(updates).Error; err != nil {
+		return err
+	}
+	issue.ReturnDate = &returnDate
+	issue.ReturnApproverID = &approverID
+	issue.ReturnBranchID = branchID
+	issue.IssueStatus = util.IssueStatusClosed
+
+	if issue.CopyID != nil {
+		var bookCopy model.BookCopy
+		if err := tx.Where("barcode = ?", *issue.CopyID).First(&bookCopy).Error; err != nil {
+			return err
+		}
+		if bookCopy.LibID != issue.LibID {
+			if err := sendCopyHome(tx, issue, &bookCopy, returnDate); err != nil {
+				return err
+			}
+		} else if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", returnedCopyStatus(&bookCopy, branchID)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := promoteHolds(tx, issue.BookID, issue.LibID, pickupWindow); err != nil {
+		return err
+	}
+	return syncCopyCounts(tx, issue.BookID, issue.LibID)
+}
+
+// deskReader finds a reader of the library by user ID, email or card number,
+// as typed or scanned at the circulation desk
+func deskReader(tx *gorm.DB, libID string, identifier string, reader *model.Users) error {
+	result := tx.Where("id = ? OR email = ? OR card_number = ?", identifier, identifier, identifier).
+		Where("lib_id = ?", libID).
+		Where("role = ?", util.ReaderRole).
+		Limit(1).
+		Find(reader)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("reader not found in library")
+	}
+	return nil
+}
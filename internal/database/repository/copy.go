@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+
+	"gorm.io/gorm"
+)
+
+// syncCopyCounts recomputes the inventory counters of a book from the status
+// of its copies. Lost and withdrawn copies no longer count towards the total.
+func syncCopyCounts(tx *gorm.DB, isbn string, libID string) error {
+	var totalCopies int64
+	if err := tx.Model(&model.BookCopy{}).
+		Where("book_id = ?", isbn).
+		Where("lib_id = ?", libID).
+		Where("status NOT IN ?", []string{util.CopyStatusLost, util.CopyStatusWithdrawn}).
+		Count(&totalCopies).Error; err != nil {
+		return err
+	}
+
+	var availableCopies int64
+	if err := tx.Model(&model.BookCopy{}).
+		Where("book_id = ?", isbn).
+		Where("lib_id = ?", libID).
+		Where("status = ?", util.CopyStatusAvailable).
+		Count(&availableCopies).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&model.BookInventory{}).Where("isbn = ?", isbn).Where("lib_id = ?", libID).Updates(map[string]interface{}{
+		"total_copies":     totalCopies,
+		"available_copies": availableCopies,
+	}).Error
+}
+
+// lockAvailableCopy locks the copy with the given barcode, or the first
+// available copy of the book when no barcode is supplied, preferring copies
+// shelved at the given branch
+func lockAvailableCopy(tx *gorm.DB, isbn string, libID string, barcode string, branchID *string, bookCopy *model.BookCopy) error {
+	if barcode == "" && branchID != nil {
+		result := tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("book_id = ?", isbn).
+			Where("lib_id = ?", libID).
+			Where("branch_id = ?", *branchID).
+			Where("status = ?", util.CopyStatusAvailable).
+			Order("barcode").
+			Limit(1).
+			Find(bookCopy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+	}
+
+	query := tx.Set("gorm:query_option", "FOR UPDATE").Where("book_id = ?", isbn).Where("lib_id = ?", libID)
+	if barcode != "" {
+		query = query.Where("barcode = ?", barcode)
+	} else {
+		query = query.Where("status = ?", util.CopyStatusAvailable).Order("barcode")
+	}
+
+	if err := query.First(bookCopy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if barcode != "" {
+				return errors.New("copy with supplied barcode not found for book")
+			}
+			return errors.New("no available copies in inventory")
+		}
+		return err
+	}
+
+	if bookCopy.Status != util.CopyStatusAvailable {
+		return errors.New("copy with supplied barcode is not available")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// readerBalance sums the ledger of a reader in a library. Charges are
+// positive and payments or waivers negative.
+func readerBalance(tx *gorm.DB, readerID string, libID string) (int64, error) {
+	var balance int64
+	err := tx.Model(&model.FineLedgerEntry{}).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Where("reader_id = ?", readerID).
+		Where("lib_id = ?", libID).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// accrueOverdueFine charges the overdue fine a loan has built up by asOf that
+// was not charged yet, so running it repeatedly never charges twice
+func accrueOverdueFine(tx *gorm.DB, issue *model.IssueRegistry, policy *model.LibraryPolicy, asOf time.Time) error {
+	dueDate, err := time.Parse(time.RFC3339, issue.ExpectedReturnDate)
+	if err != nil {
+		return err
+	}
+	if !asOf.After(dueDate) || policy.DailyFineCents == 0 {
+		return nil
+	}
+
+	daysOverdue := int64(math.Ceil(asOf.Sub(dueDate).Hours() / 24))
+	fine := daysOverdue * int64(policy.DailyFineCents)
+	if policy.FineCapCents > 0 && fine > int64(policy.FineCapCents) {
+		fine = int64(policy.FineCapCents)
+	}
+
+	var charged int64
+	if err := tx.Model(&model.FineLedgerEntry{}).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Where("issue_id = ?", issue.IssueID).
+		Where("entry_type = ?", util.FineTypeOverdue).
+		Scan(&charged).Error; err != nil {
+		return err
+	}
+	if fine <= charged {
+		return nil
+	}
+
+	entry := model.FineLedgerEntry{
+		EntryID:     util.RandomUUID(),
+		ReaderID:    issue.ReaderID,
+		LibID:       issue.LibID,
+		IssueID:     &issue.IssueID,
+		EntryType:   util.FineTypeOverdue,
+		AmountCents: fine - charged,
+		EntryDate:   asOf.Format(time.RFC3339),
+	}
+	return tx.Create(&entry).Error
+}
+
+// accrueOverdueOnReturn settles the overdue fine of a loan up to its return
+func accrueOverdueOnReturn(tx *gorm.DB, issue *model.IssueRegistry, returnTime time.Time) error {
+	dueDate, err := time.Parse(time.RFC3339, issue.ExpectedReturnDate)
+	if err != nil || !returnTime.After(dueDate) {
+		return nil
+	}
+
+	var policy model.LibraryPolicy
+	if err := readerPolicy(tx, issue.LibID, issue.ReaderID, &policy); err != nil {
+		return err
+	}
+	return accrueOverdueFine(tx, issue, &policy, returnTime)
+}
+
+func readerFines(tx *gorm.DB, readerID string, libID string, entries *[]model.FineLedgerEntry, balance *int64) error {
+	if err := tx.Where("reader_id = ?", readerID).Where("lib_id = ?", libID).Order("entry_date DESC").Find(entries).Error; err != nil {
+		return err
+	}
+
+	var err error
+	*balance, err = readerBalance(tx, readerID, libID)
+	return err
+}
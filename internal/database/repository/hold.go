@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// promoteHolds reserves available copies of a book for the oldest waiting
+// holds, moving them to ready for pickup until either runs out. Callers sync
+// the inventory counters afterwards.
+func promoteHolds(tx *gorm.DB, isbn string, libID string, pickupWindow time.Duration) error {
+	for {
+		var hold model.Hold
+		result := tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("book_id = ?", isbn).
+			Where("lib_id = ?", libID).
+			Where("status = ?", util.HoldStatusWaiting).
+			Order("queue_number").
+			Limit(1).
+			Find(&hold)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var bookCopy model.BookCopy
+		result = tx.Set("gorm:query_option", "FOR UPDATE").
+			Where("book_id = ?", isbn).
+			Where("lib_id = ?", libID).
+			Where("status = ?", util.CopyStatusAvailable).
+			Order("barcode").
+			Limit(1).
+			Find(&bookCopy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", util.CopyStatusOnHold).Error; err != nil {
+			return err
+		}
+
+		readyDate := time.Now()
+		pickupDeadline := readyDate.Add(pickupWindow)
+		if err := tx.Model(&model.Hold{}).Where("hold_id = ?", hold.HoldID).Updates(map[string]interface{}{
+			"copy_id":         bookCopy.Barcode,
+			"pickup_deadline": pickupDeadline.Format(time.RFC3339),
+			"ready_date":      readyDate.Format(time.RFC3339),
+			"status":          util.HoldStatusReady,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := notifyReader(tx, hold.ReaderID, util.NotificationHoldReady, isbn, libID, nil, map[string]string{
+			"pickup_deadline": pickupDeadline.Format(util.EmailDateFormat),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// closeHold ends an active hold with the given status. The copy reserved by a
+// ready hold goes to the next reader in the queue or back on the shelf.
+func closeHold(tx *gorm.DB, hold *model.Hold, status string, pickupWindow time.Duration) error {
+	if err := tx.Model(&model.Hold{}).Where("hold_id = ?", hold.HoldID).Update("status", status).Error; err != nil {
+		return err
+	}
+
+	if hold.Status != util.HoldStatusReady || hold.CopyID == nil {
+		return nil
+	}
+
+	if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", *hold.CopyID).Where("status = ?", util.CopyStatusOnHold).Update("status", util.CopyStatusAvailable).Error; err != nil {
+		return err
+	}
+	if err := promoteHolds(tx, hold.BookID, hold.LibID, pickupWindow); err != nil {
+		return err
+	}
+	return syncCopyCounts(tx, hold.BookID, hold.LibID)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+
+	"gorm.io/gorm"
+)
+
+// lockInterLibraryLoan loads an inter-library loan in the given status for
+// update. partyColumn names who the loan must belong to, such as
+// lender_lib_id, borrower_lib_id or reader_id.
+func lockInterLibraryLoan(tx *gorm.DB, illID string, partyColumn string, partyID string, status string, loan *model.InterLibraryLoan) error {
+	result := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("ill_id = ?", illID).
+		Where(partyColumn+" = ?", partyID).
+		Limit(1).
+		Find(loan)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("inter-library loan not found")
+	}
+	if loan.Status != status {
+		return errors.New("inter-library loan is " + loan.Status)
+	}
+	return nil
+}
+
+// stockInterLibraryTitle gives the borrowing library a catalogue entry for a
+// title it receives on loan, with no copies of its own
+func stockInterLibraryTitle(tx *gorm.DB, isbn string, lenderLibID string, borrowerLibID string) error {
+	var existing int64
+	if err := tx.Model(&model.BookInventory{}).Where("isbn = ?", isbn).Where("lib_id = ?", borrowerLibID).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var lenderBook model.BookInventory
+	if err := tx.Where("isbn = ?", isbn).Where("lib_id = ?", lenderLibID).First(&lenderBook).Error; err != nil {
+		return err
+	}
+	return tx.Create(&model.BookInventory{
+		ISBN:            isbn,
+		LibID:           &borrowerLibID,
+		Title:           lenderBook.Title,
+		Authors:         lenderBook.Authors,
+		Publisher:       lenderBook.Publisher,
+		Version:         lenderBook.Version,
+		TotalCopies:     0,
+		AvailableCopies: 0,
+	}).Error
+}
+
+func libraryName(tx *gorm.DB, libID string) (string, error) {
+	var library model.Library
+	if err := tx.Where("id = ?", libID).First(&library).Error; err != nil {
+		return "", err
+	}
+	return library.Name, nil
+}
+
+// sendCopyHome puts a copy returned at a library that does not own it in
+// transit back to its owner, moving the inter-library loan it came on along
+func sendCopyHome(tx *gorm.DB, issue *model.IssueRegistry, bookCopy *model.BookCopy, returnDate string) error {
+	if err := tx.Model(&model.BookCopy{}).Where("barcode = ?", bookCopy.Barcode).Update("status", util.CopyStatusInTransit).Error; err != nil {
+		return err
+	}
+
+	var loan model.InterLibraryLoan
+	result := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("issue_id = ?", issue.IssueID).
+		Where("status = ?", util.InterLibraryStatusIssued).
+		Limit(1).
+		Find(&loan)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", loan.ILLID).Updates(map[string]interface{}{
+		"status":              util.InterLibraryStatusReturning,
+		"return_shipped_date": returnDate,
+	}).Error; err != nil {
+		return err
+	}
+
+	borrowerName, err := libraryName(tx, loan.BorrowerLibID)
+	if err != nil {
+		return err
+	}
+	return notifyLibraryAdmins(tx, loan.LenderLibID, util.NotificationInterLibraryReturned, loan.BookID, &loan.ILLID, map[string]string{
+		"library_name": borrowerName,
+	})
+}
+
+// closeDeclaredInterLibraryLoan closes the inter-library loan of a copy the
+// borrowing library declared lost or damaged and tells the lending library
+func closeDeclaredInterLibraryLoan(tx *gorm.DB, issue *model.IssueRegistry, disposition string, closedDate string) error {
+	var loan model.InterLibraryLoan
+	result := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("issue_id = ?", issue.IssueID).
+		Where("status = ?", util.InterLibraryStatusIssued).
+		Limit(1).
+		Find(&loan)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	status := util.InterLibraryStatusLost
+	if disposition == util.IssueStatusDamaged {
+		status = util.InterLibraryStatusDamaged
+	}
+	if err := tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", loan.ILLID).Updates(map[string]interface{}{
+		"status":      status,
+		"closed_date": closedDate,
+	}).Error; err != nil {
+		return err
+	}
+
+	borrowerName, err := libraryName(tx, loan.BorrowerLibID)
+	if err != nil {
+		return err
+	}
+	return notifyLibraryAdmins(tx, loan.LenderLibID, util.NotificationInterLibraryDeclared, loan.BookID, &loan.ILLID, map[string]string{
+		"library_name": borrowerName,
+		"disposition":  disposition,
+	})
+}
+
+// reopenDeclaredInterLibraryLoan puts the inter-library loan of a copy whose
+// lost or damaged declaration was reversed back on loan, so the copy can be
+// sent home like any other return
+func reopenDeclaredInterLibraryLoan(tx *gorm.DB, issue *model.IssueRegistry) error {
+	return tx.Model(&model.InterLibraryLoan{}).
+		Where("issue_id = ?", issue.IssueID).
+		Where("status IN ?", []string{util.InterLibraryStatusLost, util.InterLibraryStatusDamaged}).
+		Updates(map[string]interface{}{
+			"status":      util.InterLibraryStatusIssued,
+			"closed_date": nil,
+		}).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/database/transaction"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	db        *gorm.DB
+	txManager *transaction.TxManager
+	mu        sync.RWMutex
+}
+
+func NewJobRepository(db *gorm.DB, txManager *transaction.TxManager) *JobRepository {
+	return &JobRepository{
+		db:        db,
+		txManager: txManager,
+	}
+}
+
+// RegisterJob records a job so replicas can claim it, keeping its schedule
+// and history when it already exists
+func (jobs *JobRepository) RegisterJob(ctx context.Context, name string, interval time.Duration) error {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+
+	return jobs.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		job := model.ScheduledJob{
+			Name:            name,
+			IntervalSeconds: int64(interval.Seconds()),
+			NextRunAt:       time.Now().Format(time.RFC3339),
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"interval_seconds"}),
+		}).Create(&job).Error
+	})
+}
+
+// ClaimJob takes the next run of a due job for this instance. Rows locked by
+// another replica are skipped, and moving next_run_at forward in the same
+// transaction keeps the run from being claimed twice.
+func (jobs *JobRepository) ClaimJob(ctx context.Context, name string, instanceID string, claimed *bool) error {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+
+	*claimed = false
+	return jobs.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var job model.ScheduledJob
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where("name = ?", name).Limit(1).Find(&job)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		nextRunAt, err := time.Parse(time.RFC3339, job.NextRunAt)
+		if err == nil && now.Before(nextRunAt) {
+			return nil
+		}
+
+		if err := tx.Model(&model.ScheduledJob{}).Where("name = ?", name).Updates(map[string]interface{}{
+			"last_run_by":     instanceID,
+			"last_started_at": now.Format(time.RFC3339),
+			"next_run_at":     now.Add(time.Duration(job.IntervalSeconds) * time.Second).Format(time.RFC3339),
+		}).Error; err != nil {
+			return err
+		}
+
+		*claimed = true
+		return nil
+	})
+}
+
+// FinishJob records the outcome of a run claimed with ClaimJob
+func (jobs *JobRepository) FinishJob(ctx context.Context, name string, startedAt time.Time, runErr error) error {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+
+	return jobs.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		finishedAt := time.Now()
+		updates := map[string]interface{}{
+			"last_duration_ms": finishedAt.Sub(startedAt).Milliseconds(),
+			"last_error":       nil,
+			"last_finished_at": finishedAt.Format(time.RFC3339),
+			"run_count":        gorm.Expr("run_count + 1"),
+		}
+		if runErr != nil {
+			updates["last_error"] = runErr.Error()
+			updates["failure_count"] = gorm.Expr("failure_count + 1")
+		}
+		return tx.Model(&model.ScheduledJob{}).Where("name = ?", name).Updates(updates).Error
+	})
+}
+
+func (jobs *JobRepository) ListJobs(ctx context.Context, scheduledJobs *[]model.ScheduledJob) error {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+
+	return jobs.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		return tx.Order("name").Find(scheduledJobs).Error
+	})
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management/backend/internal/database/transaction"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRepository_ClaimJob_Due(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewJobRepository(db, transaction.NewTxManager(db))
+	nextRunAt := time.Now().Add(-time.Minute).Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scheduled_jobs" WHERE name = $1 LIMIT $2 FOR UPDATE SKIP LOCKED`)).
+		WithArgs("expire-holds", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "interval_seconds", "next_run_at"}).
+			AddRow("expire-holds", 60, nextRunAt))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "scheduled_jobs" SET "last_run_by"=$1,"last_started_at"=$2,"next_run_at"=$3 WHERE name = $4`)).
+		WithArgs("instance1", sqlmock.AnyArg(), sqlmock.AnyArg(), "expire-holds").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	var claimed bool
+	err = repo.ClaimJob(context.Background(), "expire-holds", "instance1", &claimed)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_ClaimJob_LockedOrNotDue(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewJobRepository(db, transaction.NewTxManager(db))
+	nextRunAt := time.Now().Add(time.Minute).Format(time.RFC3339)
+
+	// Row locked by another replica is skipped
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scheduled_jobs"`)).
+		WithArgs("expire-holds", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	mock.ExpectCommit()
+
+	// Row not due yet
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scheduled_jobs"`)).
+		WithArgs("expire-holds", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "interval_seconds", "next_run_at"}).
+			AddRow("expire-holds", 60, nextRunAt))
+	mock.ExpectCommit()
+
+	var claimed bool
+	err = repo.ClaimJob(context.Background(), "expire-holds", "instance1", &claimed)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	err = repo.ClaimJob(context.Background(), "expire-holds", "instance1", &claimed)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
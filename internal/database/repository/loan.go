@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const loanDetailsQuery = `SELECT i.issue_id, i.book_id, b.title AS book_title, i.copy_id, i.issue_status, i.issue_date,
+						i.expected_return_date, i.return_date, i.renewal_count, i.overdue
+					FROM issue_registries i
+					JOIN book_inventories b ON b.isbn = i.book_id AND b.lib_id = i.lib_id
+					WHERE i.reader_id = ? AND i.lib_id = ?`
+
+// libraryReader checks that a reader belongs to the given library
+func libraryReader(tx *gorm.DB, readerID string, libID string) error {
+	var user model.Users
+	result := tx.Where("id = ?", readerID).Where("lib_id = ?", libID).Where("role = ?", util.ReaderRole).Limit(1).Find(&user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("reader not found in library")
+	}
+	return nil
+}
+
+// currentLoans lists a reader's open loans, flagging the ones already past
+// their due date even if the overdue job has not run yet
+func currentLoans(tx *gorm.DB, readerID string, libID string, loans *[]model.LoanDetails) error {
+	query := loanDetailsQuery + ` AND i.issue_status = ? ORDER BY i.expected_return_date`
+	if err := tx.Raw(query, readerID, libID, util.IssueStatusOpen).Scan(loans).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range *loans {
+		loan := &(*loans)[i]
+		dueDate, err := time.Parse(time.RFC3339, loan.ExpectedReturnDate)
+		if err == nil && now.After(dueDate) {
+			loan.Overdue = true
+		}
+	}
+	return nil
+}
+
+// loanHistory lists every loan of a reader, newest first, one page at a time
+func loanHistory(tx *gorm.DB, readerID string, libID string, page int, pageSize int, loans *[]model.LoanDetails, total *int64) error {
+	if err := tx.Model(&model.IssueRegistry{}).Where("reader_id = ?", readerID).Where("lib_id = ?", libID).Count(total).Error; err != nil {
+		return err
+	}
+
+	query := loanDetailsQuery + ` ORDER BY i.issue_date DESC LIMIT ? OFFSET ?`
+	return tx.Raw(query, readerID, libID, pageSize, (page-1)*pageSize).Scan(loans).Error
+}
+
+// readerRequests lists a reader's requests, optionally only those with the
+// given status
+func readerRequests(tx *gorm.DB, readerID string, libID string, status string, requests *[]model.RequestDetails) error {
+	query := tx.Table("request_events r").
+		Select("r.*, b.title AS book_title").
+		Joins("JOIN book_inventories b ON b.isbn = r.book_id AND b.lib_id = r.lib_id").
+		Where("r.reader_id = ?", readerID).
+		Where("r.lib_id = ?", libID)
+	if status != "" {
+		query = query.Where("r.status = ?", status)
+	}
+	return query.Order("r.request_date DESC").Scan(requests).Error
+}
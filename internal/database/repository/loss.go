@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+
+	"gorm.io/gorm"
+)
+
+// cancelLoanRequests cancels the pending return and renewal requests of a loan
+// that was closed without going through them
+func cancelLoanRequests(tx *gorm.DB, issue *model.IssueRegistry, actorID string, reason string) error {
+	var requests []model.RequestEvents
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("reader_id = ?", issue.ReaderID).
+		Where("book_id = ?", issue.BookID).
+		Where("lib_id = ?", issue.LibID).
+		Where("request_type IN ?", []string{util.ReturnRequestType, util.RenewRequestType}).
+		Where("status = ?", util.RequestStatusPending).
+		Find(&requests).Error; err != nil {
+		return err
+	}
+
+	for i := range requests {
+		if err := transitionRequest(tx, &requests[i], util.RequestStatusCancelled, &actorID, reason, nil); err != nil {
+			return err
+		}
+		if err := publishRequestEvent(tx, util.RequestEventCancelled, &requests[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declarationCharges sums what was charged for a lost or damaged declaration
+// of a loan. The fine types share their names with the loan statuses.
+func declarationCharges(tx *gorm.DB, issue *model.IssueRegistry) (int64, error) {
+	var charged int64
+	err := tx.Model(&model.FineLedgerEntry{}).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Where("issue_id = ?", issue.IssueID).
+		Where("entry_type = ?", issue.IssueStatus).
+		Scan(&charged).Error
+	return charged, err
+}
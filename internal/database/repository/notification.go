@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"fmt"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// inboxMessage is the one line shown in the inbox for a notification kind
+func inboxMessage(kind string, data map[string]string) string {
+	switch kind {
+	case util.NotificationIssueRequested:
+		return fmt.Sprintf("%s requested to issue %s", data["reader_name"], data["title"])
+	case util.NotificationRequestApproved:
+		if data["due_date"] != "" {
+			return fmt.Sprintf("Your %s request for %s was approved, due on %s", data["request_type"], data["title"], data["due_date"])
+		}
+		return fmt.Sprintf("Your %s request for %s was approved", data["request_type"], data["title"])
+	case util.NotificationRequestRejected:
+		return fmt.Sprintf("Your %s request for %s was rejected", data["request_type"], data["title"])
+	case util.NotificationDueSoon:
+		return fmt.Sprintf("%s is due on %s", data["title"], data["due_date"])
+	case util.NotificationOverdue:
+		return fmt.Sprintf("%s was due on %s and is now overdue", data["title"], data["due_date"])
+	case util.NotificationHoldReady:
+		return fmt.Sprintf("%s is ready for pickup until %s", data["title"], data["pickup_deadline"])
+	case util.NotificationInterLibraryRequested:
+		return fmt.Sprintf("%s requested %s for a reader", data["library_name"], data["title"])
+	case util.NotificationInterLibraryShipped:
+		return fmt.Sprintf("%s shipped %s for %s", data["library_name"], data["title"], data["reader_name"])
+	case util.NotificationInterLibraryReady:
+		return fmt.Sprintf("%s arrived from %s and is ready for pickup", data["title"], data["library_name"])
+	case util.NotificationInterLibraryRejected:
+		return fmt.Sprintf("%s declined your inter-library request for %s", data["library_name"], data["title"])
+	case util.NotificationInterLibraryReturned:
+		return fmt.Sprintf("%s is on its way back from %s", data["title"], data["library_name"])
+	case util.NotificationInterLibraryDeclared:
+		return fmt.Sprintf("%s declared your copy of %s %s", data["library_name"], data["title"], data["disposition"])
+	default:
+		return kind
+	}
+}
+
+// addNotification puts a notification in a user's inbox
+func addNotification(tx *gorm.DB, userID string, kind string, isbn string, requestID *string, data map[string]string) error {
+	notification := model.Notification{
+		NotificationID: util.RandomUUID(),
+		UserID:         userID,
+		Kind:           kind,
+		Message:        inboxMessage(kind, data),
+		BookID:         &isbn,
+		RequestID:      requestID,
+		CreatedDate:    time.Now().Format(time.RFC3339),
+	}
+	return tx.Create(&notification).Error
+}
+
+// notifyReader tells a reader about a book in their inbox and by email
+func notifyReader(tx *gorm.DB, readerID string, kind string, isbn string, libID string, requestID *string, data map[string]string) error {
+	var book model.BookInventory
+	if err := tx.Where("isbn = ?", isbn).Where("lib_id = ?", libID).First(&book).Error; err != nil {
+		return err
+	}
+
+	data["isbn"] = isbn
+	data["title"] = book.Title
+	if err := addNotification(tx, readerID, kind, isbn, requestID, data); err != nil {
+		return err
+	}
+	return enqueueEmail(tx, readerID, kind, data)
+}
+
+// notifyLibraryAdmins puts a notification in the inbox of every admin of a
+// library
+func notifyLibraryAdmins(tx *gorm.DB, libID string, kind string, isbn string, requestID *string, data map[string]string) error {
+	var book model.BookInventory
+	if err := tx.Where("isbn = ?", isbn).Where("lib_id = ?", libID).First(&book).Error; err != nil {
+		return err
+	}
+	data["isbn"] = isbn
+	data["title"] = book.Title
+
+	var admins []model.Users
+	if err := tx.Where("lib_id = ?", libID).Where("role = ?", util.AdminRole).Find(&admins).Error; err != nil {
+		return err
+	}
+	for _, admin := range admins {
+		if err := addNotification(tx, admin.ID, kind, isbn, requestID, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"library-management/backend/internal/util"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNotifyLibraryAdmins(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	requestID := "req123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories" WHERE isbn = $1 AND lib_id = $2`)).
+		WithArgs("1234567890", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "lib_id", "title"}).
+			AddRow("1234567890", "lib123", "Go Programming"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE lib_id = $1 AND role = $2`)).
+		WithArgs("lib123", "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).
+			AddRow("admin1", "admin").
+			AddRow("admin2", "admin"))
+	for _, adminID := range []string{"admin1", "admin2"} {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "notifications"`)).
+			WithArgs(sqlmock.AnyArg(), adminID, "issue_requested", "Reader One requested to issue Go Programming", "1234567890", requestID, false, sqlmock.AnyArg(), nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return notifyLibraryAdmins(tx, "lib123", util.NotificationIssueRequested, "1234567890", &requestID, map[string]string{
+			"reader_name": "Reader One",
+		})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxMessage(t *testing.T) {
+	data := map[string]string{"title": "Go Programming", "request_type": util.RenewRequestType}
+	assert.Equal(t, "Your renew request for Go Programming was approved", inboxMessage(util.NotificationRequestApproved, data))
+
+	data["due_date"] = "Mon, 05 Jan 2026"
+	assert.Equal(t, "Your renew request for Go Programming was approved, due on Mon, 05 Jan 2026", inboxMessage(util.NotificationRequestApproved, data))
+	assert.Equal(t, "Go Programming is due on Mon, 05 Jan 2026", inboxMessage(util.NotificationDueSoon, data))
+}
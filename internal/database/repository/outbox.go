@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/database/transaction"
+	"library-management/backend/internal/util"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db        *gorm.DB
+	txManager *transaction.TxManager
+	mu        sync.RWMutex
+}
+
+func NewOutboxRepository(db *gorm.DB, txManager *transaction.TxManager) *OutboxRepository {
+	return &OutboxRepository{
+		db:        db,
+		txManager: txManager,
+	}
+}
+
+// enqueueEmail writes an email for a user to the outbox inside the caller's
+// transaction, so it is only sent once that transaction commits
+func enqueueEmail(tx *gorm.DB, userID string, template string, data map[string]string) error {
+	var user model.Users
+	if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+		return err
+	}
+
+	templateData := map[string]string{"name": user.Name}
+	for key, value := range data {
+		templateData[key] = value
+	}
+	encoded, err := json.Marshal(templateData)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	email := model.OutboxEmail{
+		EmailID:       util.RandomUUID(),
+		UserID:        userID,
+		Recipient:     user.Email,
+		Template:      template,
+		Data:          string(encoded),
+		Status:        util.EmailStatusPending,
+		NextAttemptAt: now,
+		CreatedDate:   now,
+	}
+	return tx.Create(&email).Error
+}
+
+// ClaimEmails leases up to limit pending emails that are due for delivery.
+// Moving next_attempt_at forward hands the emails to this sender; if it dies
+// before recording the outcome they become due again once the lease runs out.
+func (outbox *OutboxRepository) ClaimEmails(ctx context.Context, limit int, lease time.Duration, emails *[]model.OutboxEmail) error {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	return outbox.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", util.EmailStatusPending).
+			Where("next_attempt_at::timestamptz <= ?", now).
+			Order("created_date").
+			Limit(limit).
+			Find(emails).Error; err != nil {
+			return err
+		}
+		if len(*emails) == 0 {
+			return nil
+		}
+
+		claimedIDs := make([]string, 0, len(*emails))
+		for _, email := range *emails {
+			claimedIDs = append(claimedIDs, email.EmailID)
+		}
+
+		return tx.Model(&model.OutboxEmail{}).Where("email_id IN ?", claimedIDs).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease).Format(time.RFC3339),
+		}).Error
+	})
+}
+
+func (outbox *OutboxRepository) MarkEmailSent(ctx context.Context, emailID string) error {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	return outbox.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&model.OutboxEmail{}).Where("email_id = ?", emailID).Updates(map[string]interface{}{
+			"last_error": nil,
+			"sent_date":  time.Now().Format(time.RFC3339),
+			"status":     util.EmailStatusSent,
+		}).Error
+	})
+}
+
+// MarkEmailFailed records a failed delivery. The email is retried after
+// retryDelay until it has been attempted maxAttempts times.
+func (outbox *OutboxRepository) MarkEmailFailed(ctx context.Context, email *model.OutboxEmail, sendErr error, maxAttempts uint, retryDelay time.Duration) error {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	return outbox.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"last_error":      sendErr.Error(),
+			"next_attempt_at": time.Now().Add(retryDelay).Format(time.RFC3339),
+		}
+		if email.Attempts+1 >= maxAttempts {
+			updates["status"] = util.EmailStatusFailed
+		}
+		return tx.Model(&model.OutboxEmail{}).Where("email_id = ?", email.EmailID).Updates(updates).Error
+	})
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/database/transaction"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository_ClaimEmails(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOutboxRepository(db, transaction.NewTxManager(db))
+	due := time.Now().Add(-time.Minute).Format(time.RFC3339)
+
+	// Only due emails are locked, and no more than the batch size
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_emails" WHERE status = $1 AND next_attempt_at::timestamptz <= $2 ORDER BY created_date LIMIT $3 FOR UPDATE SKIP LOCKED`)).
+		WithArgs("pending", sqlmock.AnyArg(), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"email_id", "recipient", "template", "status", "next_attempt_at"}).
+			AddRow("email1", "reader@example.com", "hold_ready", "pending", due))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_emails" SET "attempts"=attempts + 1,"next_attempt_at"=$1 WHERE email_id IN ($2)`)).
+		WithArgs(sqlmock.AnyArg(), "email1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	var emails []model.OutboxEmail
+	err = repo.ClaimEmails(context.Background(), 10, time.Minute, &emails)
+	assert.NoError(t, err)
+	assert.Len(t, emails, 1)
+	assert.Equal(t, "email1", emails[0].EmailID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_MarkEmailFailed(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOutboxRepository(db, transaction.NewTxManager(db))
+
+	// Retried while attempts remain
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_emails" SET "last_error"=$1,"next_attempt_at"=$2 WHERE email_id = $3`)).
+		WithArgs("connection refused", sqlmock.AnyArg(), "email1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Given up on the last attempt
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_emails" SET "last_error"=$1,"next_attempt_at"=$2,"status"=$3 WHERE email_id = $4`)).
+		WithArgs("connection refused", sqlmock.AnyArg(), "failed", "email1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	sendErr := errors.New("connection refused")
+	err = repo.MarkEmailFailed(context.Background(), &model.OutboxEmail{EmailID: "email1", Attempts: 1}, sendErr, 5, time.Minute)
+	assert.NoError(t, err)
+	err = repo.MarkEmailFailed(context.Background(), &model.OutboxEmail{EmailID: "email1", Attempts: 4}, sendErr, 5, time.Minute)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
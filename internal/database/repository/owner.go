@@ -5,6 +5,7 @@ import (
 	"errors"
 	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/database/transaction"
+	"library-management/backend/internal/util"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -59,6 +60,20 @@ func (owner *OwnerRepository) CreateLibraryWithUser(ctx context.Context, library
 			return err
 		}
 
+		policy := defaultLibraryPolicy(library.ID)
+		if err := tx.Create(&policy).Error; err != nil {
+			return err
+		}
+
+		mainBranch := model.Branch{
+			BranchID: util.RandomUUID(),
+			LibID:    library.ID,
+			Name:     util.MainBranchName,
+		}
+		if err := tx.Create(&mainBranch).Error; err != nil {
+			return err
+		}
+
 		return tx.Model(&model.Users{}).Create(&user).Error
 	})
 }
@@ -93,15 +108,30 @@ func (owner *OwnerRepository) OnboardAdmin(ctx *gin.Context, user *model.Users)
 			return errors.New("no library found with given ID")
 		}
 
+		if user.BranchID != nil {
+			if err := libraryBranch(tx, existingLibrary.ID, *user.BranchID, &model.Branch{}); err != nil {
+				return err
+			}
+		}
+
 		return tx.Create(user).Error
 	})
 }
 
-func (owner *OwnerRepository) GetLibraries(ctx *gin.Context, libraryDetails *[]model.LibraryDetails) error {
+func (owner *OwnerRepository) GetLibraries(ctx *gin.Context, ownerID string, libraryDetails *[]model.LibraryDetails) error {
 	owner.mu.Lock()
 	defer owner.mu.Unlock()
 
 	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if ownerUser.LibID == nil {
+			return errors.New("owner is not assigned to a library")
+		}
+
 		query := `SELECT l.*, u.name as owner_name, u.email as owner_email, COALESCE(b.total_books, 0) as total_books
 							FROM libraries l 
 							LEFT JOIN users u ON l.id = u.lib_id
@@ -110,10 +140,20 @@ func (owner *OwnerRepository) GetLibraries(ctx *gin.Context, libraryDetails *[]m
 								FROM book_inventories
 								GROUP BY lib_id
 							) b ON b.lib_id = l.id
-							WHERE u.role = 'owner'
+							WHERE u.role = 'owner' AND l.id = ?
 							`
 
-		return tx.Raw(query).Scan(libraryDetails).Error
+		if err := tx.Raw(query, ownerUser.LibID).Scan(libraryDetails).Error; err != nil {
+			return err
+		}
+
+		for i := range *libraryDetails {
+			(*libraryDetails)[i].Branches = make([]model.BranchDetails, 0)
+			if err := branchDetails(tx, (*libraryDetails)[i].ID, &(*libraryDetails)[i].Branches); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
@@ -125,3 +165,167 @@ func (owner *OwnerRepository) GetAdmins(ctx context.Context, admins *[]model.Use
 		return tx.Model(&model.Users{}).Where("lib_id = ?", libraryID).Where("role = ?", "admin").Find(admins).Error
 	})
 }
+
+func (owner *OwnerRepository) ResetAdminPassword(ctx context.Context, email string, hashedPassword string, ownerID string) error {
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var admin model.Users
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("email = ?", email).Where("role = ?", util.AdminRole).Where("lib_id = ?", ownerUser.LibID).First(&admin)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("admin with supplied email not found in library")
+			}
+			return result.Error
+		}
+
+		if err := tx.Model(&model.Users{}).Where("id = ?", admin.ID).Update("hashed_password", hashedPassword).Error; err != nil {
+			return err
+		}
+		return revokeUserRefreshTokens(tx, admin.ID)
+	})
+}
+
+func (owner *OwnerRepository) GetLibraryPolicy(ctx context.Context, ownerID string, policy *model.LibraryPolicy) error {
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if ownerUser.LibID == nil {
+			return errors.New("owner is not assigned to a library")
+		}
+
+		return getLibraryPolicy(tx, *ownerUser.LibID, policy)
+	})
+}
+
+// UpdateLibraryPolicy applies the supplied changes to the policy of the
+// owner's library and returns the resulting policy
+func (owner *OwnerRepository) UpdateLibraryPolicy(ctx context.Context, ownerID string, updates map[string]interface{}, policy *model.LibraryPolicy) error {
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if ownerUser.LibID == nil {
+			return errors.New("owner is not assigned to a library")
+		}
+
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("lib_id = ?", ownerUser.LibID).Limit(1).Find(policy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			*policy = defaultLibraryPolicy(*ownerUser.LibID)
+			if err := tx.Create(policy).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&model.LibraryPolicy{}).Where("lib_id = ?", ownerUser.LibID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("lib_id = ?", ownerUser.LibID).First(policy).Error
+	})
+}
+
+// AddBranch opens a new branch of the owner's library
+func (owner *OwnerRepository) AddBranch(ctx context.Context, ownerID string, branch *model.Branch) error {
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if ownerUser.LibID == nil {
+			return errors.New("owner is not assigned to a library")
+		}
+
+		var existing int64
+		if err := tx.Model(&model.Branch{}).Where("lib_id = ?", ownerUser.LibID).Where("name = ?", branch.Name).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return errors.New("branch with supplied name already exists")
+		}
+
+		branch.BranchID = util.RandomUUID()
+		branch.LibID = *ownerUser.LibID
+		return tx.Create(branch).Error
+	})
+}
+
+// ListBranches lists the branches of the owner's library
+func (owner *OwnerRepository) ListBranches(ctx context.Context, ownerID string, branches *[]model.Branch) error {
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if ownerUser.LibID == nil {
+			return errors.New("owner is not assigned to a library")
+		}
+
+		return tx.Where("lib_id = ?", ownerUser.LibID).Order("name").Find(branches).Error
+	})
+}
+
+// AssignAdminBranch moves an admin of the owner's library to the given
+// branch. A nil branch lets the admin serve the whole library.
+func (owner *OwnerRepository) AssignAdminBranch(ctx context.Context, email string, branchID *string, ownerID string) error {
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	return owner.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var ownerUser model.Users
+		result := tx.Set("gorm:query_option", "FOR SHARE").Where("id = ?", ownerID).First(&ownerUser)
+		if result.Error != nil {
+			return result.Error
+		}
+		if ownerUser.LibID == nil {
+			return errors.New("owner is not assigned to a library")
+		}
+
+		var admin model.Users
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("email = ?", email).Where("role = ?", util.AdminRole).Where("lib_id = ?", ownerUser.LibID).First(&admin)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("admin with supplied email not found in library")
+			}
+			return result.Error
+		}
+
+		if branchID != nil {
+			if err := libraryBranch(tx, *ownerUser.LibID, *branchID, &model.Branch{}); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&model.Users{}).Where("id = ?", admin.ID).Update("branch_id", branchID).Error
+	})
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/database/transaction"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerRepository_AddBranch(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOwnerRepository(db, transaction.NewTxManager(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("owner123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("owner123", "owner", "lib123"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "branches" WHERE lib_id = $1 AND name = $2`)).
+		WithArgs("lib123", "East").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "branches" ("branch_id","lib_id","name","address") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(sqlmock.AnyArg(), "lib123", "East", "1 East Street").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	branch := model.Branch{Name: "East", Address: "1 East Street"}
+	err = repo.AddBranch(context.Background(), "owner123", &branch)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, branch.BranchID)
+	assert.Equal(t, "lib123", branch.LibID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOwnerRepository_AddBranch_DuplicateName(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOwnerRepository(db, transaction.NewTxManager(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("owner123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("owner123", "owner", "lib123"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "branches" WHERE lib_id = $1 AND name = $2`)).
+		WithArgs("lib123", "East").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err = repo.AddBranch(context.Background(), "owner123", &model.Branch{Name: "East"})
+	assert.EqualError(t, err, "branch with supplied name already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOwnerRepository_AssignAdminBranch(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOwnerRepository(db, transaction.NewTxManager(db))
+	branchID := "branch456"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("owner123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("owner123", "owner", "lib123"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE email = $1 AND role = $2 AND lib_id = $3`)).
+		WithArgs("admin@example.com", "admin", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "lib_id"}).
+			AddRow("admin123", "admin@example.com", "admin", "lib123"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "branches" WHERE branch_id = $1 AND lib_id = $2`)).
+		WithArgs("branch456", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"branch_id", "lib_id", "name"}).
+			AddRow("branch456", "lib123", "East"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "branch_id"=$1 WHERE id = $2`)).
+		WithArgs("branch456", "admin123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.AssignAdminBranch(context.Background(), "admin@example.com", &branchID, "owner123")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOwnerRepository_GetLibraries(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOwnerRepository(db, transaction.NewTxManager(db))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("owner123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("owner123", "owner", "lib123"))
+
+	// Mock only the owner's library and its branch stats
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT l.*, u.name as owner_name`)).
+		WithArgs("lib123").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_name", "owner_email", "total_books"}).
+			AddRow("lib123", "City Library", "Owner", "owner@example.com", 12))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT br.*`)).
+		WithArgs("admin", "lost", "withdrawn", "available", "issued", "in_transit", "open", "lib123").
+		WillReturnRows(sqlmock.NewRows([]string{"branch_id", "lib_id", "name", "admins", "total_copies", "available_copies", "issued_copies", "in_transit_copies", "open_loans", "loans_issued", "loans_returned"}).
+			AddRow("branch123", "lib123", "Main", 2, 10, 6, 3, 1, 3, 40, 37).
+			AddRow("branch456", "lib123", "East", 1, 4, 4, 0, 0, 0, 5, 8))
+	mock.ExpectCommit()
+
+	var libraries []model.LibraryDetails
+	err = repo.GetLibraries(ctx, "owner123", &libraries)
+	assert.NoError(t, err)
+	if assert.Len(t, libraries, 1) && assert.Len(t, libraries[0].Branches, 2) {
+		assert.Equal(t, "branch123", libraries[0].Branches[0].BranchID)
+		assert.Equal(t, 2, libraries[0].Branches[0].Admins)
+		assert.Equal(t, 10, libraries[0].Branches[0].TotalCopies)
+		assert.Equal(t, 1, libraries[0].Branches[0].InTransitCopies)
+		assert.Equal(t, 37, libraries[0].Branches[0].LoansReturned)
+		assert.Equal(t, 8, libraries[0].Branches[1].LoansReturned)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOwnerRepository_ResetAdminPassword(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	repo := NewOwnerRepository(db, transaction.NewTxManager(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("owner123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("owner123", "owner", "lib123"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE email = $1 AND role = $2 AND lib_id = $3`)).
+		WithArgs("admin@example.com", "admin", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "lib_id"}).
+			AddRow("admin123", "admin@example.com", "admin", "lib123"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "hashed_password"=$1 WHERE id = $2`)).
+		WithArgs("new-hash", "admin123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock every session of the admin signed out
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked"=$1 WHERE user_id = $2`)).
+		WithArgs(true, "admin123").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	err = repo.ResetAdminPassword(context.Background(), "admin@example.com", "new-hash", "owner123")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
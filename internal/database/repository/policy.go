@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func defaultLibraryPolicy(libID string) model.LibraryPolicy {
+	return model.LibraryPolicy{
+		LibID:              libID,
+		LoanPeriodDays:     util.DefaultLoanPeriodDays,
+		MaxConcurrentLoans: util.DefaultMaxConcurrentLoans,
+		MaxRenewals:        util.DefaultMaxRenewals,
+		MaxBalanceCents:    util.DefaultMaxBalanceCents,
+	}
+}
+
+// getLibraryPolicy loads the circulation policy of a library, falling back to
+// the defaults for libraries that never saved one
+func getLibraryPolicy(tx *gorm.DB, libID string, policy *model.LibraryPolicy) error {
+	err := tx.Where("lib_id = ?", libID).First(policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		*policy = defaultLibraryPolicy(libID)
+		return nil
+	}
+	return err
+}
+
+// readerPolicy loads the library policy and applies the limits of the reader's
+// membership tier on top of it
+func readerPolicy(tx *gorm.DB, libID string, readerID string, policy *model.LibraryPolicy) error {
+	if err := getLibraryPolicy(tx, libID, policy); err != nil {
+		return err
+	}
+
+	var tier model.MembershipTier
+	result := tx.Joins("JOIN users ON users.tier_id = membership_tiers.tier_id").
+		Where("users.id = ?", readerID).
+		Where("membership_tiers.lib_id = ?", libID).
+		Limit(1).
+		Find(&tier)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		policy.LoanPeriodDays = tier.LoanPeriodDays
+		policy.MaxConcurrentLoans = tier.MaxConcurrentLoans
+		if tier.MaxRenewals != nil {
+			policy.MaxRenewals = *tier.MaxRenewals
+		}
+		if tier.DailyFineCents != nil {
+			policy.DailyFineCents = *tier.DailyFineCents
+		}
+	}
+	return nil
+}
+
+func loanPeriod(policy *model.LibraryPolicy) time.Duration {
+	return time.Duration(policy.LoanPeriodDays) * 24 * time.Hour
+}
+
+// LoanLimitError is returned when a reader already holds as many open loans
+// and pending issue requests as their library allows
+type LoanLimitError struct {
+	Limit           uint
+	OpenLoans       int64
+	PendingRequests int64
+}
+
+func (e *LoanLimitError) Error() string {
+	return fmt.Sprintf("reader has reached the limit of %d concurrent loans (%d on loan, %d pending)", e.Limit, e.OpenLoans, e.PendingRequests)
+}
+
+// checkLoanLimit counts the reader's open loans, pending issue requests and
+// inter-library requests against the library limit. excludeRequestID leaves
+// out the request or inter-library loan being approved so it is not counted
+// twice
+func checkLoanLimit(tx *gorm.DB, readerID string, libID string, policy *model.LibraryPolicy, excludeRequestID string) error {
+	var openLoans int64
+	if err := tx.Model(&model.IssueRegistry{}).Where("reader_id = ?", readerID).Where("lib_id = ?", libID).Where("issue_status = ?", util.IssueStatusOpen).Count(&openLoans).Error; err != nil {
+		return err
+	}
+
+	query := tx.Model(&model.RequestEvents{}).Where("reader_id = ?", readerID).Where("lib_id = ?", libID).Where("request_type = ?", util.IssueRequestType).Where("status = ?", util.RequestStatusPending)
+	if excludeRequestID != "" {
+		query = query.Where("req_id <> ?", excludeRequestID)
+	}
+	var pendingRequests int64
+	if err := query.Count(&pendingRequests).Error; err != nil {
+		return err
+	}
+
+	interLibraryQuery := tx.Model(&model.InterLibraryLoan{}).Where("reader_id = ?", readerID).Where("borrower_lib_id = ?", libID).Where("status IN ?", util.InterLibraryActiveStatuses)
+	if excludeRequestID != "" {
+		interLibraryQuery = interLibraryQuery.Where("ill_id <> ?", excludeRequestID)
+	}
+	var pendingInterLibrary int64
+	if err := interLibraryQuery.Count(&pendingInterLibrary).Error; err != nil {
+		return err
+	}
+	pendingRequests += pendingInterLibrary
+
+	if openLoans+pendingRequests >= int64(policy.MaxConcurrentLoans) {
+		return &LoanLimitError{
+			Limit:           policy.MaxConcurrentLoans,
+			OpenLoans:       openLoans,
+			PendingRequests: pendingRequests,
+		}
+	}
+	return nil
+}
+
+// checkBorrower refuses a new loan to a reader whose membership expired, who
+// reached the loan limit or who owes more in fines than the library allows
+func checkBorrower(tx *gorm.DB, reader *model.Users, policy *model.LibraryPolicy, excludeRequestID string) error {
+	if reader.MembershipExpiry != nil {
+		expiry, err := time.Parse(time.RFC3339, *reader.MembershipExpiry)
+		if err == nil && time.Now().After(expiry) {
+			return errors.New("membership expired on " + expiry.Format("2006-01-02") + ", renew it to borrow books")
+		}
+	}
+
+	if err := checkLoanLimit(tx, reader.ID, policy.LibID, policy, excludeRequestID); err != nil {
+		return err
+	}
+
+	balance, err := readerBalance(tx, reader.ID, policy.LibID)
+	if err != nil {
+		return err
+	}
+	if balance > int64(policy.MaxBalanceCents) {
+		return fmt.Errorf("outstanding fines of %d cents exceed the limit of %d cents", balance, policy.MaxBalanceCents)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"library-management/backend/internal/api/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderPolicy_TierOverridesLibraryPolicy(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans", "max_renewals", "daily_fine_cents", "max_balance_cents"}).
+			AddRow("lib123", 14, 5, 2, 25, 1000))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "membership_tiers"."tier_id","membership_tiers"."lib_id","membership_tiers"."name","membership_tiers"."loan_period_days","membership_tiers"."max_concurrent_loans","membership_tiers"."max_renewals","membership_tiers"."daily_fine_cents" FROM "membership_tiers" JOIN users ON users.tier_id = membership_tiers.tier_id WHERE users.id = $1 AND membership_tiers.lib_id = $2 LIMIT $3`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id", "lib_id", "name", "loan_period_days", "max_concurrent_loans", "max_renewals", "daily_fine_cents"}).
+			AddRow("tier123", "lib123", "staff", 28, 10, 4, 0))
+
+	var policy model.LibraryPolicy
+	err = readerPolicy(db, "lib123", "reader123", &policy)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(28), policy.LoanPeriodDays)
+	assert.Equal(t, uint(10), policy.MaxConcurrentLoans)
+	assert.Equal(t, uint(4), policy.MaxRenewals)
+	assert.Equal(t, uint(0), policy.DailyFineCents)
+	assert.Equal(t, uint(1000), policy.MaxBalanceCents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReaderPolicy_NoTier(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans"}).
+			AddRow("lib123", 14, 5))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "membership_tiers" JOIN users`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id"}))
+
+	var policy model.LibraryPolicy
+	err = readerPolicy(db, "lib123", "reader123", &policy)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(14), policy.LoanPeriodDays)
+	assert.Equal(t, uint(5), policy.MaxConcurrentLoans)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReaderPolicy_TierKeepsUnsetLimits(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "library_policies"`)).
+		WithArgs("lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lib_id", "loan_period_days", "max_concurrent_loans", "max_renewals", "daily_fine_cents"}).
+			AddRow("lib123", 14, 5, 2, 25))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "membership_tiers" JOIN users`)).
+		WithArgs("reader123", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"tier_id", "lib_id", "name", "loan_period_days", "max_concurrent_loans", "max_renewals", "daily_fine_cents"}).
+			AddRow("tier123", "lib123", "student", 21, 3, nil, nil))
+
+	var policy model.LibraryPolicy
+	err = readerPolicy(db, "lib123", "reader123", &policy)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(21), policy.LoanPeriodDays)
+	assert.Equal(t, uint(3), policy.MaxConcurrentLoans)
+	assert.Equal(t, uint(2), policy.MaxRenewals)
+	assert.Equal(t, uint(25), policy.DailyFineCents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -13,15 +13,17 @@ import (
 )
 
 type ReaderRepository struct {
-	db        *gorm.DB
-	txManager *transaction.TxManager
-	mu        sync.RWMutex
+	db               *gorm.DB
+	txManager        *transaction.TxManager
+	mu               sync.RWMutex
+	holdPickupWindow time.Duration
 }
 
-func NewReaderRepository(db *gorm.DB, txManager *transaction.TxManager) *ReaderRepository {
+func NewReaderRepository(db *gorm.DB, txManager *transaction.TxManager, holdPickupWindow time.Duration) *ReaderRepository {
 	return &ReaderRepository{
-		db:        db,
-		txManager: txManager,
+		db:               db,
+		txManager:        txManager,
+		holdPickupWindow: holdPickupWindow,
 	}
 }
 
@@ -70,20 +72,29 @@ func (reader *ReaderRepository) RaiseIssueRequest(ctx *gin.Context, isbn string,
 			return errors.New("access denied, provide a valid Reader email")
 		}
 		readerID := user.ID
+		if user.LibID == nil {
+			return errors.New("reader is not registered with a library")
+		}
 
 		var existingBook model.BookInventory
-		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", isbn).First(&existingBook)
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", isbn).Where("lib_id = ?", user.LibID).First(&existingBook)
 
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return errors.New("book with supplied ISBN not found in database")
 		}
 
-		if existingBook.AvailableCopies < 1 {
-			return errors.New("no books available to issue")
+		var readyHold model.Hold
+		result = tx.Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("lib_id = ?", user.LibID).Where("status = ?", util.HoldStatusReady).Limit(1).Find(&readyHold)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if existingBook.AvailableCopies < 1 && result.RowsAffected == 0 {
+			return errors.New("no books available to issue, place a hold to join the waitlist")
 		}
 
 		var existingIssueRequest model.IssueRegistry
-		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.IssueRegistry{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("issue_status = ?", "open").First(&existingIssueRequest)
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.IssueRegistry{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("issue_status = ?", util.IssueStatusOpen).First(&existingIssueRequest)
 		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return result.Error
 		}
@@ -92,7 +103,7 @@ func (reader *ReaderRepository) RaiseIssueRequest(ctx *gin.Context, isbn string,
 		}
 
 		var existingRequestEvent model.RequestEvents
-		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.RequestEvents{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).First(&existingRequestEvent)
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.RequestEvents{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("status = ?", util.RequestStatusPending).First(&existingRequestEvent)
 		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return result.Error
 		}
@@ -100,33 +111,319 @@ func (reader *ReaderRepository) RaiseIssueRequest(ctx *gin.Context, isbn string,
 			return errors.New("only one request allowed at a time")
 		}
 
+		var policy model.LibraryPolicy
+		if err := readerPolicy(tx, *user.LibID, readerID, &policy); err != nil {
+			return err
+		}
+		if err := checkBorrower(tx, &user, &policy, ""); err != nil {
+			return err
+		}
+
 		issueRequest := model.RequestEvents{
 			ReqID:        util.RandomUUID(),
 			BookID:       isbn,
+			LibID:        *user.LibID,
+			ReaderID:     readerID,
+			RequestDate:  time.Now().Format(time.RFC3339),
+			ApprovalDate: nil,
+			ApproverID:   nil,
+			RequestType:  util.IssueRequestType,
+		}
+		if err := createRequest(tx, &issueRequest); err != nil {
+			return err
+		}
+		if err := publishRequestEvent(tx, util.RequestEventRaised, &issueRequest); err != nil {
+			return err
+		}
+
+		return notifyLibraryAdmins(tx, *user.LibID, util.NotificationIssueRequested, isbn, &issueRequest.ReqID, map[string]string{
+			"reader_name": user.Name,
+		})
+	})
+}
+
+func (reader *ReaderRepository) RaiseReturnRequest(ctx *gin.Context, isbn string, readerID string) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var existingIssue model.IssueRegistry
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.IssueRegistry{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("issue_status = ?", util.IssueStatusOpen).First(&existingIssue)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("book is not issued to reader")
+			}
+			return result.Error
+		}
+
+		var existingRequestEvent model.RequestEvents
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.RequestEvents{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("status = ?", util.RequestStatusPending).First(&existingRequestEvent)
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return errors.New("only one request allowed at a time")
+		}
+
+		returnRequest := model.RequestEvents{
+			ReqID:        util.RandomUUID(),
+			BookID:       isbn,
+			LibID:        existingIssue.LibID,
+			ReaderID:     readerID,
+			RequestDate:  time.Now().Format(time.RFC3339),
+			ApprovalDate: nil,
+			ApproverID:   nil,
+			RequestType:  util.ReturnRequestType,
+		}
+		if err := createRequest(tx, &returnRequest); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventRaised, &returnRequest)
+	})
+}
+
+// RaiseRenewalRequest asks for a loan to be extended. Renewals are approved
+// immediately when the reader's library auto-approves them.
+func (reader *ReaderRepository) RaiseRenewalRequest(ctx *gin.Context, isbn string, readerID string, autoApproved *bool) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var existingIssue model.IssueRegistry
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.IssueRegistry{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("issue_status = ?", util.IssueStatusOpen).First(&existingIssue)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("book is not issued to reader")
+			}
+			return result.Error
+		}
+
+		var existingRequestEvent model.RequestEvents
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.RequestEvents{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("status = ?", util.RequestStatusPending).First(&existingRequestEvent)
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return errors.New("only one request allowed at a time")
+		}
+
+		var policy model.LibraryPolicy
+		if err := checkRenewal(tx, &existingIssue, &policy); err != nil {
+			return err
+		}
+
+		renewalRequest := model.RequestEvents{
+			ReqID:        util.RandomUUID(),
+			BookID:       isbn,
+			LibID:        existingIssue.LibID,
 			ReaderID:     readerID,
 			RequestDate:  time.Now().Format(time.RFC3339),
 			ApprovalDate: nil,
 			ApproverID:   nil,
-			RequestType:  "issue",
+			RequestType:  util.RenewRequestType,
+		}
+		if err := createRequest(tx, &renewalRequest); err != nil {
+			return err
+		}
+
+		*autoApproved = policy.AutoApproveRenewals
+		if !policy.AutoApproveRenewals {
+			return publishRequestEvent(tx, util.RequestEventRaised, &renewalRequest)
+		}
+		if err := renewIssue(tx, &existingIssue, &policy, &renewalRequest, nil); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventApproved, &renewalRequest)
+	})
+}
+
+func (reader *ReaderRepository) PlaceHold(ctx *gin.Context, isbn string, readerID string) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var user model.Users
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", readerID).First(&user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if user.LibID == nil {
+			return errors.New("reader is not registered with a library")
+		}
+
+		var existingBook model.BookInventory
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Where("isbn = ?", isbn).Where("lib_id = ?", user.LibID).First(&existingBook)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("book with supplied ISBN not found in database")
+			}
+			return result.Error
+		}
+
+		if existingBook.AvailableCopies > 0 {
+			return errors.New("book is available, raise an issue request instead")
+		}
+		// Titles stocked for an inter-library loan have no copies of their own,
+		// and loaned copies go back to the lender, so a hold could never be filled
+		if existingBook.TotalCopies == 0 {
+			return errors.New("library has no copies of this book, request an inter-library loan instead")
+		}
+
+		var existingIssue model.IssueRegistry
+		result = tx.Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("lib_id = ?", user.LibID).Where("issue_status = ?", util.IssueStatusOpen).Limit(1).Find(&existingIssue)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return errors.New("book already issued to reader")
+		}
+
+		var existingHold model.Hold
+		result = tx.Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("lib_id = ?", user.LibID).Where("status IN ?", []string{util.HoldStatusWaiting, util.HoldStatusReady}).Limit(1).Find(&existingHold)
+		if result.Error != nil {
+			return result.Error
 		}
-		return tx.Model(&model.RequestEvents{}).Create(&issueRequest).Error
+		if result.RowsAffected > 0 {
+			return errors.New("reader already has a hold on this book")
+		}
+
+		hold := model.Hold{
+			HoldID:     util.RandomUUID(),
+			BookID:     isbn,
+			LibID:      *user.LibID,
+			ReaderID:   readerID,
+			Status:     util.HoldStatusWaiting,
+			PlacedDate: time.Now().Format(time.RFC3339),
+		}
+		return tx.Create(&hold).Error
 	})
 }
 
-func (reader *ReaderRepository) GetLatestBookAvailability(ctx *gin.Context, isbn string, latestDate *string) error {
+func (reader *ReaderRepository) ListHolds(ctx *gin.Context, readerID string, holds *[]model.HoldDetails) error {
 	reader.mu.Lock()
 	defer reader.mu.Unlock()
 
 	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		query := `SELECT h.*, b.title AS book_title,
+							CASE WHEN h.status = @waiting THEN (
+								SELECT COUNT(*) FROM holds w
+								WHERE w.book_id = h.book_id AND w.lib_id = h.lib_id AND w.status = @waiting AND w.queue_number <= h.queue_number
+							) ELSE 0 END AS queue_position
+							FROM holds h
+							JOIN book_inventories b ON b.isbn = h.book_id AND b.lib_id = h.lib_id
+							WHERE h.reader_id = @reader AND h.status IN @active
+							ORDER BY h.queue_number`
+		return tx.Raw(query, map[string]interface{}{
+			"waiting": util.HoldStatusWaiting,
+			"reader":  readerID,
+			"active":  []string{util.HoldStatusWaiting, util.HoldStatusReady},
+		}).Scan(holds).Error
+	})
+}
+
+func (reader *ReaderRepository) CancelHold(ctx *gin.Context, holdID string, readerID string) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var hold model.Hold
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("hold_id = ?", holdID).Where("reader_id = ?", readerID).First(&hold)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("invalid Hold ID")
+			}
+			return result.Error
+		}
+
+		if hold.Status != util.HoldStatusWaiting && hold.Status != util.HoldStatusReady {
+			return errors.New("hold is already " + hold.Status)
+		}
+
+		return closeHold(tx, &hold, util.HoldStatusCancelled, reader.holdPickupWindow)
+	})
+}
+
+// CancelRequest withdraws one of the reader's pending requests. The request is
+// kept as cancelled, so the reader can raise a new one for the same book.
+func (reader *ReaderRepository) CancelRequest(ctx *gin.Context, requestID string, readerID string, reason string) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var request model.RequestEvents
+		result := tx.Set("gorm:query_option", "FOR UPDATE").Where("req_id = ?", requestID).Where("reader_id = ?", readerID).First(&request)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errors.New("invalid Request ID")
+			}
+			return result.Error
+		}
+
+		if request.Status != util.RequestStatusPending {
+			return errors.New("request is already " + request.Status)
+		}
+
+		if err := transitionRequest(tx, &request, util.RequestStatusCancelled, &readerID, reason, nil); err != nil {
+			return err
+		}
+		return publishRequestEvent(tx, util.RequestEventCancelled, &request)
+	})
+}
+
+func (reader *ReaderRepository) GetRequestHistory(ctx *gin.Context, requestID string, readerID string, changes *[]model.RequestStatusChange) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var request model.RequestEvents
+		result := tx.Where("req_id = ?", requestID).Where("reader_id = ?", readerID).Limit(1).Find(&request)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invalid Request ID")
+		}
+
+		return requestHistory(tx, requestID, changes)
+	})
+}
+
+func (reader *ReaderRepository) GetFines(ctx *gin.Context, readerID string, entries *[]model.FineLedgerEntry, balance *int64) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var user model.Users
+		if err := tx.Where("id = ?", readerID).First(&user).Error; err != nil {
+			return err
+		}
+		if user.LibID == nil {
+			return errors.New("reader is not registered with a library")
+		}
+
+		return readerFines(tx, readerID, *user.LibID, entries, balance)
+	})
+}
+
+func (reader *ReaderRepository) GetLatestBookAvailability(ctx *gin.Context, isbn string, readerID string, latestDate *string) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var user model.Users
+		if err := tx.Where("id = ?", readerID).First(&user).Error; err != nil {
+			return err
+		}
+
 		query := `
             SELECT expected_return_date
             FROM issue_registries
-            WHERE book_id = ?
+            WHERE book_id = ? AND lib_id = ? AND issue_status = ?
             ORDER BY expected_return_date ASC
             LIMIT 1
         `
 		return tx.Set("gorm:query_option", "FOR UPDATE").
-			Raw(query, isbn).
+			Raw(query, isbn, user.LibID, util.IssueStatusOpen).
 			Scan(latestDate).
 			Error
 	})
@@ -140,3 +437,174 @@ func (reader *ReaderRepository) GetBooks(ctx *gin.Context, books *[]model.BookIn
 		return tx.Model(&model.BookInventory{}).Find(&books).Error
 	})
 }
+
+// readerLibrary looks up the library a reader is registered with
+func readerLibrary(tx *gorm.DB, readerID string, libID *string) error {
+	var user model.Users
+	if err := tx.Where("id = ?", readerID).First(&user).Error; err != nil {
+		return err
+	}
+	if user.LibID == nil {
+		return errors.New("reader is not registered with a library")
+	}
+
+	*libID = *user.LibID
+	return nil
+}
+
+func (reader *ReaderRepository) GetCurrentLoans(ctx *gin.Context, readerID string, loans *[]model.LoanDetails) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var libID string
+		if err := readerLibrary(tx, readerID, &libID); err != nil {
+			return err
+		}
+		return currentLoans(tx, readerID, libID, loans)
+	})
+}
+
+func (reader *ReaderRepository) GetLoanHistory(ctx *gin.Context, readerID string, page int, pageSize int, loans *[]model.LoanDetails, total *int64) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var libID string
+		if err := readerLibrary(tx, readerID, &libID); err != nil {
+			return err
+		}
+		return loanHistory(tx, readerID, libID, page, pageSize, loans, total)
+	})
+}
+
+func (reader *ReaderRepository) GetRequests(ctx *gin.Context, readerID string, status string, requests *[]model.RequestDetails) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var libID string
+		if err := readerLibrary(tx, readerID, &libID); err != nil {
+			return err
+		}
+		return readerRequests(tx, readerID, libID, status, requests)
+	})
+}
+
+// FindInterLibraryCopies lists the other libraries on the platform that have a
+// copy of the book available to lend
+func (reader *ReaderRepository) FindInterLibraryCopies(ctx *gin.Context, isbn string, readerID string, libraries *[]model.InterLibraryAvailability) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var libID string
+		if err := readerLibrary(tx, readerID, &libID); err != nil {
+			return err
+		}
+
+		query := `SELECT b.lib_id AS library_id, l.name AS library_name, b.title, b.available_copies
+					FROM book_inventories b
+					JOIN libraries l ON l.id = b.lib_id
+					WHERE b.isbn = ? AND b.lib_id <> ? AND b.available_copies > 0
+					ORDER BY l.name`
+		return tx.Raw(query, isbn, libID).Scan(libraries).Error
+	})
+}
+
+// RequestInterLibraryLoan asks another library to lend a book to the reader.
+// The request counts toward the reader's loan limit at their own library.
+func (reader *ReaderRepository) RequestInterLibraryLoan(ctx *gin.Context, isbn string, lenderLibID string, readerID string, loan *model.InterLibraryLoan) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var user model.Users
+		if err := tx.Where("id = ?", readerID).First(&user).Error; err != nil {
+			return err
+		}
+		if user.LibID == nil {
+			return errors.New("reader is not registered with a library")
+		}
+		if *user.LibID == lenderLibID {
+			return errors.New("book is held by your own library, raise an issue request instead")
+		}
+
+		var lenderBook model.BookInventory
+		result := tx.Where("isbn = ?", isbn).Where("lib_id = ?", lenderLibID).Limit(1).Find(&lenderBook)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("book with supplied ISBN not found in lending library")
+		}
+		if lenderBook.AvailableCopies < 1 {
+			return errors.New("lending library has no copies available")
+		}
+
+		var activeLoans int64
+		if err := tx.Model(&model.InterLibraryLoan{}).Where("reader_id = ?", readerID).Where("book_id = ?", isbn).Where("status IN ?", []string{util.InterLibraryStatusRequested, util.InterLibraryStatusShipped, util.InterLibraryStatusReceived, util.InterLibraryStatusIssued}).Count(&activeLoans).Error; err != nil {
+			return err
+		}
+		if activeLoans > 0 {
+			return errors.New("book already requested from another library")
+		}
+
+		var policy model.LibraryPolicy
+		if err := readerPolicy(tx, *user.LibID, readerID, &policy); err != nil {
+			return err
+		}
+		if err := checkBorrower(tx, &user, &policy, ""); err != nil {
+			return err
+		}
+
+		*loan = model.InterLibraryLoan{
+			ILLID:         util.RandomUUID(),
+			BookID:        isbn,
+			LenderLibID:   lenderLibID,
+			BorrowerLibID: *user.LibID,
+			ReaderID:      readerID,
+			Status:        util.InterLibraryStatusRequested,
+			RequestDate:   time.Now().Format(time.RFC3339),
+		}
+		if err := tx.Create(loan).Error; err != nil {
+			return err
+		}
+
+		borrowerName, err := libraryName(tx, *user.LibID)
+		if err != nil {
+			return err
+		}
+		return notifyLibraryAdmins(tx, lenderLibID, util.NotificationInterLibraryRequested, isbn, &loan.ILLID, map[string]string{
+			"library_name": borrowerName,
+		})
+	})
+}
+
+func (reader *ReaderRepository) GetInterLibraryLoans(ctx *gin.Context, readerID string, loans *[]model.InterLibraryLoan) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		return tx.Where("reader_id = ?", readerID).Order("request_date DESC").Find(loans).Error
+	})
+}
+
+// CancelInterLibraryLoan withdraws an inter-library request the lending
+// library has not shipped yet
+func (reader *ReaderRepository) CancelInterLibraryLoan(ctx *gin.Context, illID string, readerID string) error {
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+
+	return reader.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		var loan model.InterLibraryLoan
+		if err := lockInterLibraryLoan(tx, illID, "reader_id", readerID, util.InterLibraryStatusRequested, &loan); err != nil {
+			return err
+		}
+
+		return tx.Model(&model.InterLibraryLoan{}).Where("ill_id = ?", illID).Updates(map[string]interface{}{
+			"status":      util.InterLibraryStatusCancelled,
+			"closed_date": time.Now().Format(time.RFC3339),
+		}).Error
+	})
+}
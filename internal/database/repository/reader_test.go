@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management/backend/internal/database/transaction"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderRepository_CancelRequest(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	reader := NewReaderRepository(db, transaction.NewTxManager(db), 72*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events" WHERE req_id = $1 AND reader_id = $2 ORDER BY "request_events"."req_id" LIMIT $3`)).
+		WithArgs("req123", "reader123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow("req123", "1234567890", "lib123", "reader123", "issue", "pending"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "request_events" SET "closed_date"=$1,"status"=$2 WHERE req_id = $3`)).
+		WithArgs(sqlmock.AnyArg(), "cancelled", "req123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "request_status_changes"`)).
+		WithArgs(sqlmock.AnyArg(), "req123", "pending", "cancelled", "reader123", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs("request_events", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = reader.CancelRequest(&gin.Context{}, "req123", "reader123", "")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReaderRepository_CancelRequest_NotPending(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	reader := NewReaderRepository(db, transaction.NewTxManager(db), 72*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events"`)).
+		WithArgs("req123", "reader123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "reader_id", "status"}).
+			AddRow("req123", "reader123", "approved"))
+	mock.ExpectRollback()
+
+	err = reader.CancelRequest(&gin.Context{}, "req123", "reader123", "")
+	assert.EqualError(t, err, "request is already approved")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReaderRepository_CancelRequest_WithReason(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	reader := NewReaderRepository(db, transaction.NewTxManager(db), 72*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "request_events"`)).
+		WithArgs("req123", "reader123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"req_id", "book_id", "lib_id", "reader_id", "request_type", "status"}).
+			AddRow("req123", "1234567890", "lib123", "reader123", "issue", "pending"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "request_events" SET "closed_date"=$1,"status"=$2,"status_reason"=$3 WHERE req_id = $4`)).
+		WithArgs(sqlmock.AnyArg(), "cancelled", "found a copy elsewhere", "req123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "request_status_changes"`)).
+		WithArgs(sqlmock.AnyArg(), "req123", "pending", "cancelled", "reader123", "found a copy elsewhere", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs("request_events", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = reader.CancelRequest(&gin.Context{}, "req123", "reader123", "found a copy elsewhere")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReaderRepository_PlaceHold_NoOwnedCopies(t *testing.T) {
+	db, mock, err := setupTestDB(t)
+	assert.NoError(t, err)
+
+	reader := NewReaderRepository(db, transaction.NewTxManager(db), 72*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("reader123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "lib_id"}).
+			AddRow("reader123", "reader", "lib123"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_inventories" WHERE isbn = $1 AND lib_id = $2`)).
+		WithArgs("1234567890", "lib123", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn", "lib_id", "total_copies", "available_copies"}).
+			AddRow("1234567890", "lib123", 0, 0))
+	mock.ExpectRollback()
+
+	err = reader.PlaceHold(&gin.Context{}, "1234567890", "reader123")
+	assert.EqualError(t, err, "library has no copies of this book, request an inter-library loan instead")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// checkRenewal refuses to renew a loan past the library's renewal limit or
+// while other readers are waiting for the title
+func checkRenewal(tx *gorm.DB, issue *model.IssueRegistry, policy *model.LibraryPolicy) error {
+	if err := readerPolicy(tx, issue.LibID, issue.ReaderID, policy); err != nil {
+		return err
+	}
+
+	if issue.RenewalCount >= policy.MaxRenewals {
+		return errors.New("loan has reached the maximum number of renewals")
+	}
+
+	var waitingHolds int64
+	if err := tx.Model(&model.Hold{}).
+		Where("book_id = ?", issue.BookID).
+		Where("lib_id = ?", issue.LibID).
+		Where("status = ?", util.HoldStatusWaiting).
+		Count(&waitingHolds).Error; err != nil {
+		return err
+	}
+	if waitingHolds > 0 {
+		return errors.New("cannot renew a book other readers are waiting for")
+	}
+	return nil
+}
+
+// renewIssue extends the loan by another loan period from its due date, or
+// from now when the loan is already overdue, and marks the request approved
+func renewIssue(tx *gorm.DB, issue *model.IssueRegistry, policy *model.LibraryPolicy, request *model.RequestEvents, approverID *string) error {
+	approvalDate := time.Now()
+	dueDate, err := time.Parse(time.RFC3339, issue.ExpectedReturnDate)
+	if err != nil || dueDate.Before(approvalDate) {
+		dueDate = approvalDate
+	}
+
+	if err := tx.Model(&model.IssueRegistry{}).Where("issue_id = ?", issue.IssueID).Updates(map[string]interface{}{
+		"expected_return_date": dueDate.Add(loanPeriod(policy)).Format(time.RFC3339),
+		"due_reminder_sent":    false,
+		"overdue":              false,
+		"renewal_count":        issue.RenewalCount + 1,
+	}).Error; err != nil {
+		return err
+	}
+
+	reason := ""
+	if approverID == nil {
+		reason = "approved automatically by library policy"
+	}
+	return transitionRequest(tx, request, util.RequestStatusApproved, approverID, reason, map[string]interface{}{
+		"approval_date": approvalDate.Format(time.RFC3339),
+		"approver_id":   approverID,
+	})
+}
+
+// notifyRenewal tells the reader the new due date of a renewed loan
+func notifyRenewal(tx *gorm.DB, issue *model.IssueRegistry, requestID string) error {
+	var renewedIssue model.IssueRegistry
+	if err := tx.Where("issue_id = ?", issue.IssueID).First(&renewedIssue).Error; err != nil {
+		return err
+	}
+
+	data := map[string]string{"request_type": util.RenewRequestType}
+	if dueDate, err := time.Parse(time.RFC3339, renewedIssue.ExpectedReturnDate); err == nil {
+		data["due_date"] = dueDate.Format(util.EmailDateFormat)
+	}
+	return notifyReader(tx, issue.ReaderID, util.NotificationRequestApproved, issue.BookID, issue.LibID, &requestID, data)
+}
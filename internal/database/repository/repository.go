@@ -2,6 +2,7 @@ package repository
 
 import (
 	"library-management/backend/internal/database/transaction"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,17 +13,21 @@ type Repository struct {
 	AdminRepository  *AdminRepository
 	ReaderRepository *ReaderRepository
 	SharedRepository *SharedRepository
+	JobRepository    *JobRepository
+	OutboxRepository *OutboxRepository
 	txManager        *transaction.TxManager
 }
 
-func NewRepository(db *gorm.DB) *Repository {
+func NewRepository(db *gorm.DB, holdPickupWindow time.Duration) *Repository {
 	txManager := transaction.NewTxManager(db)
 	return &Repository{
 		AuthRepository:   NewAuthRepository(db, txManager),
 		OwnerRepository:  NewOwnerRepository(db, txManager),
-		AdminRepository:  NewAdminRepository(db, txManager),
-		ReaderRepository: NewReaderRepository(db, txManager),
+		AdminRepository:  NewAdminRepository(db, txManager, holdPickupWindow),
+		ReaderRepository: NewReaderRepository(db, txManager, holdPickupWindow),
 		SharedRepository: NewSharedRepository(db, txManager),
+		JobRepository:    NewJobRepository(db, txManager),
+		OutboxRepository: NewOutboxRepository(db, txManager),
 		txManager:        txManager,
 	}
 }
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createRequest raises a new pending request and starts its status history
+func createRequest(tx *gorm.DB, request *model.RequestEvents) error {
+	request.Status = util.RequestStatusPending
+	if err := tx.Model(&model.RequestEvents{}).Create(request).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&model.RequestStatusChange{
+		ChangeID:    util.RandomUUID(),
+		ReqID:       request.ReqID,
+		ToStatus:    util.RequestStatusPending,
+		ActorID:     &request.ReaderID,
+		ChangedDate: request.RequestDate,
+	}).Error
+}
+
+// transitionRequest moves a request to a new status along with any other
+// column updates, and records who moved it and why. Requests that end
+// without being approved keep their row and get a closed date.
+func transitionRequest(tx *gorm.DB, request *model.RequestEvents, status string, actorID *string, reason string, updates map[string]interface{}) error {
+	now := time.Now().Format(time.RFC3339)
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = status
+	if reason != "" {
+		updates["status_reason"] = reason
+	}
+	if status != util.RequestStatusApproved {
+		updates["closed_date"] = now
+	}
+
+	if err := tx.Model(&model.RequestEvents{}).Where("req_id = ?", request.ReqID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&model.RequestStatusChange{
+		ChangeID:    util.RandomUUID(),
+		ReqID:       request.ReqID,
+		FromStatus:  request.Status,
+		ToStatus:    status,
+		ActorID:     actorID,
+		Reason:      reason,
+		ChangedDate: now,
+	}).Error
+}
+
+func requestHistory(tx *gorm.DB, requestID string, changes *[]model.RequestStatusChange) error {
+	return tx.Where("req_id = ?", requestID).Order("changed_date").Find(changes).Error
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"library-management/backend/internal/api/model"
 	"library-management/backend/internal/database/transaction"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -91,7 +93,11 @@ func (shared *SharedRepository) SearchBookByISBN(ctx *gin.Context, isbn string,
 			return result.Error
 		}
 
-		return tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.BookInventory{}).Where("isbn = ?", isbn).Where("lib_id = ?", user.LibID).First(&book).Error
+		result = tx.Set("gorm:query_option", "FOR UPDATE").Model(&model.BookInventory{}).Where("isbn = ?", isbn).Where("lib_id = ?", user.LibID).First(&book)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return errors.New("book with supplied ISBN not found in library")
+		}
+		return result.Error
 	})
 }
 
@@ -108,3 +114,50 @@ func (shared *SharedRepository) GetBooks(ctx *gin.Context, books *[]model.BookIn
 		return tx.Model(&model.BookInventory{}).Where("lib_id = ?", user.LibID).Find(&books).Error
 	})
 }
+
+func (shared *SharedRepository) ListNotifications(ctx *gin.Context, userID string, unreadOnly bool, notifications *[]model.Notification) error {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+
+	return shared.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		query := tx.Where("user_id = ?", userID)
+		if unreadOnly {
+			query = query.Where("read = ?", false)
+		}
+		return query.Order("created_date DESC").Find(notifications).Error
+	})
+}
+
+// MarkNotificationsRead marks the given notifications of a user as read, or
+// all of them when no IDs are given
+func (shared *SharedRepository) MarkNotificationsRead(ctx *gin.Context, userID string, notificationIDs []string) error {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+
+	return shared.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		query := tx.Model(&model.Notification{}).Where("user_id = ?", userID).Where("read = ?", false)
+		if len(notificationIDs) > 0 {
+			query = query.Where("notification_id IN ?", notificationIDs)
+		}
+		return query.Updates(map[string]interface{}{
+			"read":      true,
+			"read_date": time.Now().Format(time.RFC3339),
+		}).Error
+	})
+}
+
+func (shared *SharedRepository) DeleteNotification(ctx *gin.Context, userID string, notificationID string) error {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+
+	return shared.txManager.ExecuteInTx(ctx, func(tx *gorm.DB) error {
+		result := tx.Where("notification_id = ?", notificationID).Where("user_id = ?", userID).Delete(&model.Notification{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("notification not found")
+		}
+		return nil
+	})
+}
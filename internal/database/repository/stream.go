@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"encoding/json"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// publishRequestEvent announces a change to a request on the request events
+// channel. Postgres delivers the notification only when the transaction
+// commits, so listeners never see changes that were rolled back.
+func publishRequestEvent(tx *gorm.DB, event string, request *model.RequestEvents) error {
+	payload, err := json.Marshal(model.RequestStreamEvent{
+		Event:       event,
+		RequestID:   request.ReqID,
+		RequestType: request.RequestType,
+		LibID:       request.LibID,
+		BookID:      request.BookID,
+		ReaderID:    request.ReaderID,
+		EventDate:   time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec("SELECT pg_notify(?, ?)", util.RequestEventsChannel, string(payload)).Error
+}
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/database/repository"
+	"log"
+	"time"
+)
+
+const (
+	dispatchBatchSize = 50
+	dispatchLease     = 5 * time.Minute
+	maxSendAttempts   = 5
+	retryDelay        = 10 * time.Minute
+)
+
+// Dispatcher delivers emails queued in the outbox by committed transactions
+type Dispatcher struct {
+	outbox    *repository.OutboxRepository
+	transport Transport
+}
+
+func NewDispatcher(outbox *repository.OutboxRepository, transport Transport) *Dispatcher {
+	return &Dispatcher{
+		outbox:    outbox,
+		transport: transport,
+	}
+}
+
+// DispatchPending sends the emails that are due. Failed sends are recorded on
+// the email and retried later, so only outbox errors fail the run.
+func (dispatcher *Dispatcher) DispatchPending(ctx context.Context) error {
+	var emails []model.OutboxEmail
+	if err := dispatcher.outbox.ClaimEmails(ctx, dispatchBatchSize, dispatchLease, &emails); err != nil {
+		return err
+	}
+
+	for i := range emails {
+		email := &emails[i]
+		sendErr := dispatcher.send(ctx, email)
+		if sendErr == nil {
+			if err := dispatcher.outbox.MarkEmailSent(ctx, email.EmailID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Printf("failed to send email %s: %v", email.EmailID, sendErr)
+		if err := dispatcher.outbox.MarkEmailFailed(ctx, email, sendErr, maxSendAttempts, retryDelay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dispatcher *Dispatcher) send(ctx context.Context, email *model.OutboxEmail) error {
+	data := map[string]string{}
+	if err := json.Unmarshal([]byte(email.Data), &data); err != nil {
+		return fmt.Errorf("invalid email data: %w", err)
+	}
+
+	message, err := Render(email.Template, email.Recipient, data)
+	if err != nil {
+		return err
+	}
+	return dispatcher.transport.Send(ctx, message)
+}
@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"library-management/backend/internal/util"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	message, err := Render(util.NotificationRequestApproved, "reader@example.com", map[string]string{
+		"name":         "Reader",
+		"request_type": util.IssueRequestType,
+		"title":        "Go Programming",
+		"isbn":         "978-3-16-148410-0",
+		"due_date":     "Mon, 05 Jan 2026",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "reader@example.com", message.To)
+	assert.Equal(t, "Your issue request for Go Programming was approved", message.Subject)
+	assert.Contains(t, message.Body, "Please return the book by Mon, 05 Jan 2026.")
+
+	message, err = Render(util.NotificationRequestApproved, "reader@example.com", map[string]string{"request_type": util.RenewRequestType})
+	assert.NoError(t, err)
+	assert.NotContains(t, message.Body, "<no value>")
+	assert.NotContains(t, message.Body, "Please return")
+
+	_, err = Render("unknown", "reader@example.com", nil)
+	assert.Error(t, err)
+}
+
+func TestFileTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	transport, err := NewFileTransport(path)
+	assert.NoError(t, err)
+
+	err = transport.Send(context.Background(), Message{To: "reader@example.com", Subject: "Hold ready", Body: "Collect it\n"})
+	assert.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), "To: reader@example.com\r\n")
+	assert.Contains(t, string(contents), "Subject: Hold ready\r\n")
+	assert.Contains(t, string(contents), "Collect it\r\n")
+}
+
+func TestFormatMessageHeaders(t *testing.T) {
+	message := string(formatMessage("library@example.com", Message{
+		To:      "reader@example.com",
+		Subject: "Your hold for Evil\r\nBcc: victim@example.com is ready",
+		Body:    "Collect it\n",
+	}))
+	assert.Contains(t, message, "Subject: Your hold for Evil Bcc: victim@example.com is ready\r\n")
+	assert.NotContains(t, message, "\r\nBcc:")
+
+	message = string(formatMessage("", Message{To: "reader@example.com", Subject: "Hold ready: Café", Body: ""}))
+	assert.Contains(t, message, "Subject: =?UTF-8?q?Hold_ready:_Caf=C3=A9?=\r\n")
+}
+
+func TestNewSMTPTransport(t *testing.T) {
+	_, err := NewSMTPTransport("", "library@example.com", "", "")
+	assert.Error(t, err)
+
+	transport, err := NewSMTPTransport("localhost:1025", "library@example.com", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, transport.auth)
+
+	transport, err = NewSMTPTransport("smtp.example.com:587", "library@example.com", "user", "secret")
+	assert.NoError(t, err)
+	assert.NotNil(t, transport.auth)
+}
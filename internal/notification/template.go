@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"fmt"
+	"library-management/backend/internal/util"
+	"strings"
+	"text/template"
+)
+
+// Message is a rendered email ready to hand to a Transport
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type emailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func newEmailTemplate(name string, subject string, body string) emailTemplate {
+	return emailTemplate{
+		subject: template.Must(template.New(name + "_subject").Option("missingkey=zero").Parse(subject)),
+		body:    template.Must(template.New(name + "_body").Option("missingkey=zero").Parse(body)),
+	}
+}
+
+var templates = map[string]emailTemplate{
+	util.NotificationRequestApproved: newEmailTemplate(util.NotificationRequestApproved,
+		"Your {{.request_type}} request for {{.title}} was approved",
+		"Hi {{.name}},\n\nYour {{.request_type}} request for {{.title}} (ISBN {{.isbn}}) was approved.\n{{if .due_date}}Please return the book by {{.due_date}}.\n{{end}}"),
+	util.NotificationRequestRejected: newEmailTemplate(util.NotificationRequestRejected,
+		"Your {{.request_type}} request for {{.title}} was rejected",
+		"Hi {{.name}},\n\nYour {{.request_type}} request for {{.title}} (ISBN {{.isbn}}) was rejected by the library.\n"),
+	util.NotificationDueSoon: newEmailTemplate(util.NotificationDueSoon,
+		"{{.title}} is due on {{.due_date}}",
+		"Hi {{.name}},\n\n{{.title}} (ISBN {{.isbn}}) is due back on {{.due_date}}. Return or renew it to avoid overdue fines.\n"),
+	util.NotificationOverdue: newEmailTemplate(util.NotificationOverdue,
+		"{{.title}} is overdue",
+		"Hi {{.name}},\n\n{{.title}} (ISBN {{.isbn}}) was due back on {{.due_date}}. Fines accrue daily until it is returned.\n"),
+	util.NotificationHoldReady: newEmailTemplate(util.NotificationHoldReady,
+		"{{.title}} is ready for pickup",
+		"Hi {{.name}},\n\nThe copy of {{.title}} (ISBN {{.isbn}}) you placed a hold on is ready. Collect it by {{.pickup_deadline}} or it goes to the next reader.\n"),
+	util.NotificationInterLibraryReady: newEmailTemplate(util.NotificationInterLibraryReady,
+		"{{.title}} arrived from {{.library_name}}",
+		"Hi {{.name}},\n\nThe copy of {{.title}} (ISBN {{.isbn}}) you requested from {{.library_name}} has arrived and is ready for pickup at your library.\n"),
+	util.NotificationInterLibraryRejected: newEmailTemplate(util.NotificationInterLibraryRejected,
+		"Your inter-library request for {{.title}} was declined",
+		"Hi {{.name}},\n\n{{.library_name}} declined your inter-library request for {{.title}} (ISBN {{.isbn}}).\n"),
+}
+
+// Render builds the message for a named template
+func Render(name string, to string, data map[string]string) (Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %s", name)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{To: to, Subject: subject.String(), Body: body.String()}, nil
+}
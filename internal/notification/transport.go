@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport delivers rendered messages
+type Transport interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SMTPTransport sends through an SMTP relay. Without a username it skips
+// authentication, which is what local stand-ins such as MailHog expect.
+type SMTPTransport struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPTransport(addr string, from string, username string, password string) (*SMTPTransport, error) {
+	if addr == "" || from == "" {
+		return nil, fmt.Errorf("smtp transport needs a server address and a from address")
+	}
+
+	transport := &SMTPTransport{addr: addr, from: from}
+	if username != "" {
+		host := addr
+		if i := strings.LastIndex(addr, ":"); i != -1 {
+			host = addr[:i]
+		}
+		transport.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return transport, nil
+}
+
+func (transport *SMTPTransport) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(transport.addr, transport.auth, transport.from, []string{message.To}, formatMessage(transport.from, message))
+}
+
+// WriterTransport writes messages to a file or the log instead of sending
+// them, for development
+type WriterTransport struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewFileTransport(path string) (*WriterTransport, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterTransport{writer: file}, nil
+}
+
+func NewLogTransport() *WriterTransport {
+	return &WriterTransport{writer: log.Writer()}
+}
+
+func (transport *WriterTransport) Send(ctx context.Context, message Message) error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	_, err := transport.writer.Write(append(formatMessage("", message), '\n'))
+	return err
+}
+
+func formatMessage(from string, message Message) []byte {
+	var builder strings.Builder
+	if from != "" {
+		builder.WriteString("From: " + from + "\r\n")
+	}
+	builder.WriteString("To: " + headerValue(message.To) + "\r\n")
+	builder.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", headerValue(message.Subject)) + "\r\n")
+	builder.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(builder.String())
+}
+
+// headerValue folds line breaks into spaces so values built from user data,
+// such as book titles, cannot start a header of their own
+func headerValue(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}), " ")
+}
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"library-management/backend/internal/database/repository"
+	"library-management/backend/internal/util"
+	"log"
+	"time"
+)
+
+// Job is a periodic task. Every replica registers the same jobs and each run
+// is claimed by exactly one of them through the scheduled_jobs table.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(context.Context) error
+}
+
+type Scheduler struct {
+	jobs         []Job
+	repository   *repository.JobRepository
+	instanceID   string
+	pollInterval time.Duration
+}
+
+func NewScheduler(jobRepository *repository.JobRepository, pollInterval time.Duration) *Scheduler {
+	return &Scheduler{
+		repository:   jobRepository,
+		instanceID:   util.RandomUUID(),
+		pollInterval: pollInterval,
+	}
+}
+
+func (scheduler *Scheduler) Register(job Job) {
+	scheduler.jobs = append(scheduler.jobs, job)
+}
+
+// Start registers the jobs and polls for due runs until ctx is cancelled
+func (scheduler *Scheduler) Start(ctx context.Context) error {
+	for _, job := range scheduler.jobs {
+		if err := scheduler.repository.RegisterJob(ctx, job.Name, job.Interval); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(scheduler.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			scheduler.RunDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// RunDue runs every job whose next run this instance manages to claim
+func (scheduler *Scheduler) RunDue(ctx context.Context) {
+	for _, job := range scheduler.jobs {
+		var claimed bool
+		if err := scheduler.repository.ClaimJob(ctx, job.Name, scheduler.instanceID, &claimed); err != nil {
+			log.Printf("failed to claim job %s: %v", job.Name, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		startedAt := time.Now()
+		runErr := job.Run(ctx)
+		if runErr != nil {
+			log.Printf("job %s failed: %v", job.Name, runErr)
+		}
+		if err := scheduler.repository.FinishJob(ctx, job.Name, startedAt, runErr); err != nil {
+			log.Printf("failed to record run of job %s: %v", job.Name, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	subscriberBuffer = 16
+	reconnectDelay   = 5 * time.Second
+)
+
+// Broker fans request events out to the admins connected to this replica.
+// Events arrive through Postgres LISTEN/NOTIFY, so a request handled by any
+// replica reaches every subscriber of its library.
+type Broker struct {
+	dsn         string
+	mu          sync.RWMutex
+	subscribers map[chan model.RequestStreamEvent]string
+}
+
+func NewBroker(dsn string) *Broker {
+	return &Broker{
+		dsn:         dsn,
+		subscribers: make(map[chan model.RequestStreamEvent]string),
+	}
+}
+
+// Start listens for request events until ctx is cancelled, reconnecting when
+// the listening connection drops
+func (broker *Broker) Start(ctx context.Context) {
+	go func() {
+		for {
+			err := broker.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("request event listener stopped: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+		}
+	}()
+}
+
+func (broker *Broker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, broker.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+util.RequestEventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event model.RequestStreamEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("invalid request event payload: %v", err)
+			continue
+		}
+		broker.Publish(event)
+	}
+}
+
+// Subscribe returns the events of a library and a function that ends the
+// subscription
+func (broker *Broker) Subscribe(libID string) (<-chan model.RequestStreamEvent, func()) {
+	events := make(chan model.RequestStreamEvent, subscriberBuffer)
+
+	broker.mu.Lock()
+	broker.subscribers[events] = libID
+	broker.mu.Unlock()
+
+	return events, func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+
+		if _, ok := broker.subscribers[events]; ok {
+			delete(broker.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Publish hands an event to the subscribers of its library. Subscribers that
+// fall behind miss events rather than holding up the others.
+func (broker *Broker) Publish(event model.RequestStreamEvent) {
+	broker.mu.RLock()
+	defer broker.mu.RUnlock()
+
+	for events, libID := range broker.subscribers {
+		if libID != event.LibID {
+			continue
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package stream
+
+import (
+	"library-management/backend/internal/api/model"
+	"library-management/backend/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_PublishFiltersByLibrary(t *testing.T) {
+	broker := NewBroker("")
+	events, unsubscribe := broker.Subscribe("lib1")
+	otherEvents, unsubscribeOther := broker.Subscribe("lib2")
+	defer unsubscribeOther()
+
+	broker.Publish(model.RequestStreamEvent{Event: util.RequestEventRaised, RequestID: "req1", LibID: "lib1"})
+
+	event := <-events
+	assert.Equal(t, "req1", event.RequestID)
+	assert.Len(t, otherEvents, 0)
+
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok)
+
+	// Publishing after unsubscribing must not panic on the closed channel
+	broker.Publish(model.RequestStreamEvent{Event: util.RequestEventRaised, RequestID: "req2", LibID: "lib1"})
+	unsubscribe()
+}
+
+func TestBroker_SlowSubscriberDropsEvents(t *testing.T) {
+	broker := NewBroker("")
+	events, unsubscribe := broker.Subscribe("lib1")
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		broker.Publish(model.RequestStreamEvent{Event: util.RequestEventRaised, LibID: "lib1"})
+	}
+	assert.Len(t, events, subscriberBuffer)
+}
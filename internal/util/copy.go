@@ -0,0 +1,21 @@
+package util
+
+import "strings"
+
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusIssued    = "issued"
+	CopyStatusOnHold    = "on_hold"
+	CopyStatusDamaged   = "damaged"
+	CopyStatusLost      = "lost"
+	CopyStatusWithdrawn = "withdrawn"
+	CopyStatusInTransit = "in_transit"
+)
+
+// MainBranchName names the branch every library starts with
+const MainBranchName = "Main"
+
+// RandomBarcode generates a random accession barcode for a book copy
+func RandomBarcode() string {
+	return strings.ToUpper(RandomString(12))
+}
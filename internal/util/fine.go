@@ -0,0 +1,11 @@
+package util
+
+const (
+	FineTypeOverdue = "overdue"
+	FineTypeLost    = "lost"
+	FineTypeDamaged = "damaged"
+	FineTypePayment = "payment"
+	FineTypeWaiver  = "waiver"
+)
+
+const DefaultMaxBalanceCents = 1000
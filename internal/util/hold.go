@@ -0,0 +1,9 @@
+package util
+
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusExpired   = "expired"
+	HoldStatusCancelled = "cancelled"
+)
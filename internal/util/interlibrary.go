@@ -0,0 +1,21 @@
+package util
+
+// Inter-library loan statuses, in the order a loan normally goes through them
+const (
+	InterLibraryStatusRequested = "requested"
+	InterLibraryStatusShipped   = "shipped"
+	InterLibraryStatusReceived  = "received"
+	InterLibraryStatusIssued    = "issued"
+	InterLibraryStatusReturning = "returning"
+	InterLibraryStatusReturned  = "returned"
+	InterLibraryStatusRejected  = "rejected"
+	InterLibraryStatusCancelled = "cancelled"
+
+	// A loan whose copy the borrowing library declared lost or damaged
+	InterLibraryStatusLost    = "lost"
+	InterLibraryStatusDamaged = "damaged"
+)
+
+// InterLibraryActiveStatuses are the statuses of a request the reader is still
+// waiting on
+var InterLibraryActiveStatuses = []string{InterLibraryStatusRequested, InterLibraryStatusShipped, InterLibraryStatusReceived}
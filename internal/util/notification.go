@@ -0,0 +1,27 @@
+package util
+
+// Notification kinds. The ones readers receive double as email template names.
+const (
+	NotificationIssueRequested  = "issue_requested"
+	NotificationRequestApproved = "request_approved"
+	NotificationRequestRejected = "request_rejected"
+	NotificationDueSoon         = "due_soon"
+	NotificationOverdue         = "overdue"
+	NotificationHoldReady       = "hold_ready"
+
+	NotificationInterLibraryRequested = "inter_library_requested"
+	NotificationInterLibraryShipped   = "inter_library_shipped"
+	NotificationInterLibraryReady     = "inter_library_ready"
+	NotificationInterLibraryRejected  = "inter_library_rejected"
+	NotificationInterLibraryReturned  = "inter_library_returned"
+	NotificationInterLibraryDeclared  = "inter_library_declared"
+)
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// EmailDateFormat is how dates appear in email bodies
+const EmailDateFormat = "Mon, 02 Jan 2006"
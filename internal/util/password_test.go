@@ -0,0 +1,27 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPassword(t *testing.T) {
+	password := RandomString(12)
+
+	hashedPassword, err := HashPassword(password)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hashedPassword)
+	assert.NotEqual(t, password, hashedPassword)
+
+	err = CheckPassword(password, hashedPassword)
+	assert.NoError(t, err)
+
+	err = CheckPassword(RandomString(12), hashedPassword)
+	assert.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+
+	otherHashedPassword, err := HashPassword(password)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashedPassword, otherHashedPassword)
+}
@@ -34,6 +34,12 @@ func RandomUUID() string {
 	return userID.String()
 }
 
+// RandomCardNumber generates a library card number of three groups of four
+// digits, easy to read out or type at a desk
+func RandomCardNumber() string {
+	return fmt.Sprintf("%04d-%04d-%04d", rand.Intn(10000), rand.Intn(10000), rand.Intn(10000))
+}
+
 // RandomEmail generates a random email
 func RandomEmail() string {
 	return fmt.Sprintf("%s@email.com", RandomString(8))
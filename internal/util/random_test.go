@@ -0,0 +1,12 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomCardNumber(t *testing.T) {
+	cardNumber := RandomCardNumber()
+	assert.Regexp(t, `^\d{4}-\d{4}-\d{4}$`, cardNumber)
+}
@@ -0,0 +1,44 @@
+package util
+
+const (
+	IssueRequestType  = "issue"
+	ReturnRequestType = "return"
+	RenewRequestType  = "renew"
+)
+
+const (
+	RequestStatusPending   = "pending"
+	RequestStatusApproved  = "approved"
+	RequestStatusRejected  = "rejected"
+	RequestStatusExpired   = "expired"
+	RequestStatusCancelled = "cancelled"
+)
+
+// A loan declared lost or damaged is closed with that status instead of
+// IssueStatusClosed
+const (
+	IssueStatusOpen    = "open"
+	IssueStatusClosed  = "closed"
+	IssueStatusLost    = "lost"
+	IssueStatusDamaged = "damaged"
+)
+
+const (
+	DefaultLoanPeriodDays     = 7
+	DefaultMaxConcurrentLoans = 5
+	DefaultMaxRenewals        = 2
+)
+
+// RequestEventsChannel is the Postgres NOTIFY channel request changes are
+// published on
+const RequestEventsChannel = "request_events"
+
+const (
+	RequestEventRaised    = "raised"
+	RequestEventApproved  = "approved"
+	RequestEventRejected  = "rejected"
+	RequestEventExpired   = "expired"
+	RequestEventCancelled = "cancelled"
+)
+
+const DefaultPageSize = 20
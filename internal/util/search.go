@@ -0,0 +1,11 @@
+package util
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ContainsPattern builds a LIKE pattern matching values that contain text
+// literally, for use with ESCAPE '\'
+func ContainsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
@@ -0,0 +1,12 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainsPattern(t *testing.T) {
+	assert.Equal(t, "%ali%", ContainsPattern("ali"))
+	assert.Equal(t, `%50\%\_off\\%`, ContainsPattern(`50%_off\`))
+}
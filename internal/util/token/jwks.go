@@ -0,0 +1,127 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// KeySetProvider is implemented by makers whose tokens can be verified with
+// published public keys
+type KeySetProvider interface {
+	JWKS() JSONWebKeySet
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+func newJSONWebKey(kid string, publicKey crypto.PublicKey) JSONWebKey {
+	jwk := JSONWebKey{
+		KeyID: kid,
+		Use:   "sig",
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Algorithm = "RS256"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Algorithm = "EdDSA"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+	return jwk
+}
+
+func sortJSONWebKeys(keys []JSONWebKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].KeyID < keys[j].KeyID
+	})
+}
+
+// LoadSigningKeys reads every <kid>.pem file in dir. Private keys (PKCS#1 or
+// PKCS#8) can sign and verify, public keys (PKIX) are retired keys that only
+// verify.
+func LoadSigningKeys(dir string) ([]SigningKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]SigningKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parseSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+	return keys, nil
+}
+
+func parseSigningKey(kid string, data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("no PEM data found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		return SigningKey{ID: kid, PrivateKey: privateKey, PublicKey: privateKey.Public()}, nil
+	case "PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return SigningKey{}, fmt.Errorf("unsupported private key type %T", privateKey)
+		}
+		return SigningKey{ID: kid, PrivateKey: signer, PublicKey: signer.Public()}, nil
+	case "PUBLIC KEY":
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		return SigningKey{ID: kid, PublicKey: publicKey}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+}
@@ -0,0 +1,140 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeySize = 2048
+
+// SigningKey is a key identified by its kid. Retired keys carry no private
+// key and are only used to verify tokens issued before a rotation.
+type SigningKey struct {
+	ID         string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// AsymmetricJWTMaker signs tokens with RS256 or EdDSA so that verifiers only
+// need the public keys published in the JWKS
+type AsymmetricJWTMaker struct {
+	activeKey SigningKey
+	keys      map[string]SigningKey
+}
+
+func NewAsymmetricJWTMaker(keys []SigningKey, activeKeyID string) (*AsymmetricJWTMaker, error) {
+	maker := &AsymmetricJWTMaker{
+		keys: make(map[string]SigningKey, len(keys)),
+	}
+
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("signing key id must not be empty")
+		}
+		if _, ok := maker.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key id %s", key.ID)
+		}
+		if key.PublicKey == nil && key.PrivateKey != nil {
+			key.PublicKey = key.PrivateKey.Public()
+		}
+		if _, err := signingMethodFor(key.PublicKey); err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", key.ID, err)
+		}
+		maker.keys[key.ID] = key
+	}
+
+	activeKey, ok := maker.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %s not found", activeKeyID)
+	}
+	if activeKey.PrivateKey == nil {
+		return nil, fmt.Errorf("active signing key %s has no private key", activeKeyID)
+	}
+	maker.activeKey = activeKey
+
+	return maker, nil
+}
+
+// CreateToken creates a new token for a specific username and duration
+func (maker *AsymmetricJWTMaker) CreateToken(userID string, role string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, role, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	method, err := signingMethodFor(maker.activeKey.PublicKey)
+	if err != nil {
+		return "", payload, err
+	}
+
+	jwtToken := jwt.NewWithClaims(method, payload)
+	jwtToken.Header["kid"] = maker.activeKey.ID
+	token, err := jwtToken.SignedString(maker.activeKey.PrivateKey)
+	return token, payload, err
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *AsymmetricJWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (any, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		key, ok := maker.keys[kid]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		method, err := signingMethodFor(key.PublicKey)
+		if err != nil || token.Method.Alg() != method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.PublicKey, nil
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+	if err != nil && !errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, ok := jwtToken.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return payload, ErrExpiredToken
+	}
+	return payload, nil
+}
+
+// JWKS returns the public keys that tokens from this maker can be verified with
+func (maker *AsymmetricJWTMaker) JWKS() JSONWebKeySet {
+	keySet := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(maker.keys))}
+	for _, key := range maker.keys {
+		keySet.Keys = append(keySet.Keys, newJSONWebKey(key.ID, key.PublicKey))
+	}
+	sortJSONWebKeys(keySet.Keys)
+	return keySet
+}
+
+func signingMethodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if key.Size()*8 < minRSAKeySize {
+			return nil, fmt.Errorf("invalid key size: RSA keys must be at least %d bits", minRSAKeySize)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+}
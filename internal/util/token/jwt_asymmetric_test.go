@@ -0,0 +1,158 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"library-management/backend/internal/util"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRSASigningKey(t *testing.T, kid string) SigningKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return SigningKey{ID: kid, PrivateKey: privateKey}
+}
+
+func newEd25519SigningKey(t *testing.T, kid string) SigningKey {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	return SigningKey{ID: kid, PrivateKey: privateKey}
+}
+
+func TestAsymmetricJWToken(t *testing.T) {
+	for _, key := range []SigningKey{newRSASigningKey(t, "rsa-1"), newEd25519SigningKey(t, "ed-1")} {
+		maker, err := NewAsymmetricJWTMaker([]SigningKey{key}, key.ID)
+		assert.NoError(t, err)
+
+		userID := util.RandomUUID()
+		role := util.ReaderRole
+
+		token, payload, err := maker.CreateToken(userID, role, time.Minute)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, payload)
+
+		payload, err = maker.VerifyToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, payload.UserID)
+		assert.Equal(t, role, payload.Role)
+
+		expired, _, err := maker.CreateToken(userID, role, -time.Minute)
+		assert.NoError(t, err)
+		payload, err = maker.VerifyToken(expired)
+		assert.EqualError(t, err, ErrExpiredToken.Error())
+		assert.NotNil(t, payload)
+	}
+}
+
+func TestAsymmetricJWTokenRotation(t *testing.T) {
+	oldKey := newEd25519SigningKey(t, "2024-01")
+	newKey := newRSASigningKey(t, "2024-02")
+
+	oldMaker, err := NewAsymmetricJWTMaker([]SigningKey{oldKey}, oldKey.ID)
+	assert.NoError(t, err)
+	oldToken, _, err := oldMaker.CreateToken(util.RandomUUID(), util.AdminRole, time.Minute)
+	assert.NoError(t, err)
+
+	retiredKey := SigningKey{ID: oldKey.ID, PublicKey: oldKey.PrivateKey.Public()}
+	rotatedMaker, err := NewAsymmetricJWTMaker([]SigningKey{retiredKey, newKey}, newKey.ID)
+	assert.NoError(t, err)
+
+	_, err = rotatedMaker.VerifyToken(oldToken)
+	assert.NoError(t, err)
+
+	newToken, _, err := rotatedMaker.CreateToken(util.RandomUUID(), util.AdminRole, time.Minute)
+	assert.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Payload{})
+	assert.NoError(t, err)
+	assert.Equal(t, newKey.ID, parsed.Header["kid"])
+	assert.Equal(t, "RS256", parsed.Header["alg"])
+
+	_, err = oldMaker.VerifyToken(newToken)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	_, err = NewAsymmetricJWTMaker([]SigningKey{retiredKey, newKey}, retiredKey.ID)
+	assert.Error(t, err)
+}
+
+func TestAsymmetricJWTokenRejectsHMAC(t *testing.T) {
+	key := newRSASigningKey(t, "rsa-1")
+	maker, err := NewAsymmetricJWTMaker([]SigningKey{key}, key.ID)
+	assert.NoError(t, err)
+
+	payload, err := NewPayload(util.RandomUUID(), util.OwnerRole, time.Minute)
+	assert.NoError(t, err)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	jwtToken.Header["kid"] = key.ID
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(key.PrivateKey.Public())
+	assert.NoError(t, err)
+	token, err := jwtToken.SignedString(publicKeyDER)
+	assert.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+}
+
+func TestAsymmetricJWTMakerJWKS(t *testing.T) {
+	rsaKey := newRSASigningKey(t, "b-rsa")
+	edKey := newEd25519SigningKey(t, "a-ed")
+
+	maker, err := NewAsymmetricJWTMaker([]SigningKey{rsaKey, edKey}, rsaKey.ID)
+	assert.NoError(t, err)
+
+	keySet := maker.JWKS()
+	assert.Len(t, keySet.Keys, 2)
+
+	assert.Equal(t, "a-ed", keySet.Keys[0].KeyID)
+	assert.Equal(t, "OKP", keySet.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", keySet.Keys[0].Curve)
+	assert.NotEmpty(t, keySet.Keys[0].X)
+
+	assert.Equal(t, "b-rsa", keySet.Keys[1].KeyID)
+	assert.Equal(t, "RSA", keySet.Keys[1].KeyType)
+	assert.Equal(t, "AQAB", keySet.Keys[1].E)
+	assert.NotEmpty(t, keySet.Keys[1].N)
+}
+
+func TestLoadSigningKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey := newRSASigningKey(t, "active")
+	rsaDER := x509.MarshalPKCS1PrivateKey(rsaKey.PrivateKey.(*rsa.PrivateKey))
+	writePEM(t, filepath.Join(dir, "active.pem"), "RSA PRIVATE KEY", rsaDER)
+
+	edKey := newEd25519SigningKey(t, "retired")
+	edDER, err := x509.MarshalPKIXPublicKey(edKey.PrivateKey.Public())
+	assert.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "retired.pem"), "PUBLIC KEY", edDER)
+
+	keys, err := LoadSigningKeys(dir)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	maker, err := NewAsymmetricJWTMaker(keys, "active")
+	assert.NoError(t, err)
+	assert.Len(t, maker.JWKS().Keys, 2)
+
+	_, err = NewAsymmetricJWTMaker(keys, "retired")
+	assert.Error(t, err)
+
+	_, err = LoadSigningKeys(t.TempDir())
+	assert.Error(t, err)
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+}
@@ -6,9 +6,16 @@ import (
 
 // Maker is an interface for managing tokens
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(userID string, name string, email string, role string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token for a specific user, role and duration
+	CreateToken(userID string, role string, duration time.Duration) (string, *Payload, error)
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
 }
+
+var (
+	_ Maker = (*JWTMaker)(nil)
+	_ Maker = (*AsymmetricJWTMaker)(nil)
+	_ Maker = (*PasetoLocalMaker)(nil)
+	_ Maker = (*PasetoPublicMaker)(nil)
+)
@@ -0,0 +1,152 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"library-management/backend/internal/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomEd25519Seed(t *testing.T) string {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	return hex.EncodeToString(privateKey.Seed())
+}
+
+func TestPasetoLocalToken(t *testing.T) {
+	maker, err := NewPasetoLocalMaker(util.RandomString(32))
+	assert.NoError(t, err)
+
+	userID := util.RandomUUID()
+	role := util.ReaderRole
+	duration := 15 * time.Minute
+
+	issuedAt := time.Now()
+	expires := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(userID, role, duration)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	assert.NoError(t, err)
+
+	assert.NotZero(t, payload.ID)
+	assert.Equal(t, userID, payload.UserID)
+	assert.Equal(t, role, payload.Role)
+	assert.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	assert.WithinDuration(t, expires, payload.Expires, time.Second)
+}
+
+func TestExpiredPasetoLocalToken(t *testing.T) {
+	maker, err := NewPasetoLocalMaker(util.RandomString(32))
+	assert.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomUUID(), util.AdminRole, -time.Minute)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	assert.EqualError(t, err, ErrExpiredToken.Error())
+	assert.NotNil(t, payload)
+}
+
+func TestTamperedPasetoLocalToken(t *testing.T) {
+	maker, err := NewPasetoLocalMaker(util.RandomString(32))
+	assert.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomUUID(), util.AdminRole, time.Minute)
+	assert.NoError(t, err)
+
+	tampered := []byte(token)
+	last := len(tampered) - 5
+	if tampered[last] == 'A' {
+		tampered[last] = 'B'
+	} else {
+		tampered[last] = 'A'
+	}
+
+	payload, err := maker.VerifyToken(string(tampered))
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+
+	otherMaker, err := NewPasetoLocalMaker(util.RandomString(32))
+	assert.NoError(t, err)
+
+	payload, err = otherMaker.VerifyToken(token)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+}
+
+func TestInvalidPasetoLocalKeySize(t *testing.T) {
+	_, err := NewPasetoLocalMaker(util.RandomString(31))
+	assert.Error(t, err)
+}
+
+func TestPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(randomEd25519Seed(t))
+	assert.NoError(t, err)
+
+	userID := util.RandomUUID()
+	role := util.OwnerRole
+	duration := 15 * time.Minute
+
+	token, payload, err := maker.CreateToken(userID, role, duration)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, payload.UserID)
+	assert.Equal(t, role, payload.Role)
+
+	otherMaker, err := NewPasetoPublicMaker(randomEd25519Seed(t))
+	assert.NoError(t, err)
+
+	payload, err = otherMaker.VerifyToken(token)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(randomEd25519Seed(t))
+	assert.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomUUID(), util.AdminRole, -time.Minute)
+	assert.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	assert.EqualError(t, err, ErrExpiredToken.Error())
+	assert.NotNil(t, payload)
+}
+
+func TestPasetoPurposeMismatch(t *testing.T) {
+	localMaker, err := NewPasetoLocalMaker(util.RandomString(32))
+	assert.NoError(t, err)
+	publicMaker, err := NewPasetoPublicMaker(randomEd25519Seed(t))
+	assert.NoError(t, err)
+
+	localToken, _, err := localMaker.CreateToken(util.RandomUUID(), util.AdminRole, time.Minute)
+	assert.NoError(t, err)
+	publicToken, _, err := publicMaker.CreateToken(util.RandomUUID(), util.AdminRole, time.Minute)
+	assert.NoError(t, err)
+
+	_, err = publicMaker.VerifyToken(localToken)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	_, err = localMaker.VerifyToken(publicToken)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+}
+
+func TestInvalidPasetoPublicKey(t *testing.T) {
+	_, err := NewPasetoPublicMaker("not-hex")
+	assert.Error(t, err)
+
+	_, err = NewPasetoPublicMaker(hex.EncodeToString([]byte(util.RandomString(16))))
+	assert.Error(t, err)
+}
@@ -0,0 +1,22 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshToken(t *testing.T) {
+	refreshToken, err := NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, refreshToken)
+
+	otherRefreshToken, err := NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, refreshToken, otherRefreshToken)
+
+	hash := HashRefreshToken(refreshToken)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashRefreshToken(refreshToken))
+	assert.NotEqual(t, hash, HashRefreshToken(otherRefreshToken))
+}